@@ -1,6 +1,6 @@
 module github.com/ALiwoto/wotoImgur
 
-go 1.18
+go 1.21
 
 require golang.org/x/text v0.3.7 // indirect
 
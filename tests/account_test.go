@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestAccount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/account/woto", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":7,"url":"woto","reputation":12.5},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/account/me/settings", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			_ = r.ParseForm()
+			if r.Form.Get("show_mature") != "false" || r.Form.Get("bio") != "hi" || r.Form.Has("public_images") {
+				t.Error("unexpected settings form: ", r.Form.Encode())
+			}
+			_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"account_url":"woto","album_privacy":"hidden"},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/account/me/images/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"a"}],"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/account/me/albums/0", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"alb"}],"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/account/woto/albums/ids/0", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":["alb","alb2"],"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/account/woto/images/count", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":42,"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	account, err := client.GetAccount("woto")
+	if err != nil || account.ID != 7 || account.Reputation != 12.5 {
+		t.Error("unexpected account: ", account, err)
+	}
+
+	if _, err = client.GetAccount(""); err == nil {
+		t.Error("referring to the current account should require authentication")
+	}
+
+	ids, err := client.GetAccountAlbumIDs("woto", 0)
+	if err != nil || len(ids) != 2 {
+		t.Error("unexpected album ids: ", ids, err)
+	}
+
+	count, err := client.GetAccountImageCount("woto")
+	if err != nil || count != 42 {
+		t.Error("unexpected image count: ", count, err)
+	}
+
+	_ = client.SetToken(&wotoImgur.OAuthToken{AccessToken: "access"})
+
+	settings, err := client.GetAccountSettings()
+	if err != nil || settings.AlbumPrivacy != wotoImgur.AlbumPrivacyHidden {
+		t.Error("unexpected settings: ", settings, err)
+	}
+
+	showMature := false
+	err = client.UpdateAccountSettings(&wotoImgur.AccountSettingsOptions{Bio: "hi", ShowMature: &showMature})
+	if err != nil {
+		t.Error("when tried to update settings: ", err.Error())
+	}
+
+	images, err := client.GetAccountImages(wotoImgur.AccountMe, 1)
+	if err != nil || len(images) != 1 {
+		t.Error("unexpected account images: ", images, err)
+	}
+
+	albums, err := client.GetAccountAlbums("", 0)
+	if err != nil || len(albums) != 1 || albums[0].ID != "alb" {
+		t.Error("unexpected account albums: ", albums, err)
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestAlbumManagement(t *testing.T) {
+	requests := map[string]string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/album", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Error("unexpected method for creating album: ", r.Method)
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("privacy") != "hidden" || len(r.Form["deletehashes[]"]) != 2 {
+			t.Error("unexpected album form: ", r.Form.Encode())
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"alb","deletehash":"albdel"},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/album/albdel/", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requests[r.Method+" "+strings.TrimPrefix(r.URL.Path, "/3/album/albdel/")] = strings.Join(r.Form["ids[]"], ",")
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/album/albdel", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		requests[r.Method] = r.Form.Get("title") + strings.Join(r.Form["ids[]"], ",")
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	album, err := client.CreateAlbum(&wotoImgur.AlbumOptions{
+		Title:        "woto",
+		Privacy:      wotoImgur.AlbumPrivacyHidden,
+		DeleteHashes: []string{"d1", "d2"},
+	})
+	if err != nil {
+		t.Error("when tried to create album: ", err.Error())
+		return
+	}
+
+	if album.ID != "alb" || album.DeleteHash != "albdel" {
+		t.Error("unexpected album: ", album)
+		return
+	}
+
+	steps := []error{
+		client.UpdateAlbum(album.DeleteHash, &wotoImgur.AlbumOptions{Title: "new"}),
+		client.AddImagesToAlbum(album.DeleteHash, []string{"a", "b"}),
+		client.RemoveImagesFromAlbum(album.DeleteHash, []string{"a"}),
+		client.SetAlbumImages(album.DeleteHash, []string{"c"}),
+		client.DeleteAlbum(album.DeleteHash),
+	}
+	for i, err := range steps {
+		if err != nil {
+			t.Error("album step ", i, " failed: ", err.Error())
+		}
+	}
+
+	expected := map[string]string{
+		"PUT":                "new",
+		"POST add":           "a,b",
+		"POST remove_images": "a",
+		"POST":               "c",
+		"DELETE":             "",
+	}
+	for key, value := range expected {
+		if got, ok := requests[key]; !ok || got != value {
+			t.Error("unexpected request ", key, ": ", got)
+		}
+	}
+
+	if err = client.AddImagesToAlbum(album.DeleteHash, nil); err == nil {
+		t.Error("expected an error when adding no images")
+	}
+}
+
+func TestAlbumImages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/album/alb/images", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-ClientRemaining", "99")
+		_, _ = w.Write([]byte(`{"data":[{"id":"a"},{"id":"b"},{"id":"c"}],"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/album/alb/image/b", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"b","title":"second"},"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	images, err := client.GetAlbumImages("alb")
+	if err != nil {
+		t.Error("when tried to get album images: ", err.Error())
+		return
+	}
+
+	if len(images) != 3 || images[2].ID != "c" {
+		t.Error("unexpected album images: ", images)
+		return
+	}
+
+	if images[0].Limit == nil || images[0].Limit.ClientRemaining != 99 {
+		t.Error("rate limit should be attached to the images")
+	}
+
+	image, err := client.GetAlbumImage("alb", "b")
+	if err != nil || image.Title != "second" {
+		t.Error("unexpected album image: ", image, err)
+	}
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestBaseURL(t *testing.T) {
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mirror/3/image/abc":
+			_, _ = w.Write([]byte(`{"data":{"id":"abc"},"success":true,"status":200}`))
+		case "/mirror/3/image":
+			_, _ = w.Write([]byte(`{"data":{"id":"mirrored"},"success":true,"status":200}`))
+		default:
+			t.Error("unexpected request to the mirror: ", r.Method, " ", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mirror.Close()
+
+	uploads := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/uploads/image" || r.Method != http.MethodPost {
+			t.Error("unexpected request to the upload server: ", r.Method, " ", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"uploaded"},"success":true,"status":200}`))
+	}))
+	defer uploads.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: http.DefaultClient,
+		BaseURL:    mirror.URL + "/mirror/3",
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	info, err := client.GetImageInfo("abc")
+	if err != nil || info.ID != "abc" {
+		t.Error("unexpected image info: ", info, err)
+	}
+
+	info, err = client.UploadImageFromReader(bytes.NewReader([]byte("content")), nil)
+	if err != nil || info.ID != "mirrored" {
+		t.Error("uploads should default to the base url: ", info, err)
+	}
+
+	client, err = wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: http.DefaultClient,
+		BaseURL:    mirror.URL + "/mirror/3/",
+		UploadURL:  uploads.URL + "/uploads/",
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.GetImageInfo("abc"); err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+	}
+
+	info, err = client.UploadImage([]byte("aGVsbG8="), "", "base64", "", "")
+	if err != nil || info.ID != "uploaded" {
+		t.Error("unexpected uploaded image: ", info, err)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+	"github.com/ALiwoto/wotoImgur/wotoImgurtest"
+)
+
+func TestRecorder(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+	baseURL := server.BaseURL()
+
+	recorder, err := wotoImgurtest.NewRecorder(cassettePath, wotoImgurtest.ModeAuto, server.Client().Transport)
+	if err != nil || recorder.Mode() != wotoImgurtest.ModeRecord {
+		server.Close()
+		t.Error("when tried to get new recorder: ", err)
+		return
+	}
+
+	config := &wotoImgur.ClientConfig{
+		Token:       &wotoImgur.OAuthToken{AccessToken: "access"},
+		RapidAPIKey: "secret-key",
+		HTTPClient:  recorder.Client(),
+	}
+	client, err := server.NewImgurClient("client-id", config)
+	if err != nil {
+		server.Close()
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	uploaded, err := client.UploadImageFromReader(bytes.NewReader([]byte("content")),
+		&wotoImgur.UploadOptions{Name: "a.jpg", Title: "woto"})
+	if err != nil {
+		server.Close()
+		t.Error("when tried to upload image: ", err.Error())
+		return
+	}
+
+	recorded, err := client.GetImageInfo(uploaded.ID)
+	server.Close()
+	if err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+		return
+	}
+
+	b, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Error("when tried to read cassette: ", err.Error())
+		return
+	}
+	if strings.Contains(string(b), "secret-key") || strings.Contains(string(b), "Bearer access") {
+		t.Error("cassette should not contain any secret: ", string(b))
+	}
+
+	recorder, err = wotoImgurtest.NewRecorder(cassettePath, wotoImgurtest.ModeAuto, nil)
+	if err != nil || recorder.Mode() != wotoImgurtest.ModeReplay {
+		t.Error("when tried to get replaying recorder: ", err)
+		return
+	}
+
+	config.BaseURL = baseURL
+	config.HTTPClient = recorder.Client()
+	client, err = wotoImgur.NewImgurClient("client-id", config)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	replayed, err := client.UploadImageFromReader(bytes.NewReader([]byte("content")),
+		&wotoImgur.UploadOptions{Name: "a.jpg", Title: "woto"})
+	if err != nil || replayed.ID != uploaded.ID || replayed.DeleteHash != uploaded.DeleteHash {
+		t.Error("unexpected replayed upload: ", replayed, err)
+	}
+
+	info, err := client.GetImageInfo(uploaded.ID)
+	if err != nil || info.Title != recorded.Title || info.ID != recorded.ID {
+		t.Error("unexpected replayed image info: ", info, err)
+	}
+
+	if _, err = client.GetImageInfo(uploaded.ID); err == nil {
+		t.Error("interactions should only be replayed once")
+	}
+
+	_, err = client.UploadImageFromReader(bytes.NewReader([]byte("changed")),
+		&wotoImgur.UploadOptions{Name: "a.jpg", Title: "woto"})
+	if err == nil {
+		t.Error("uploads of different files should not match")
+	}
+}
+
+func TestRecorderRedactsTokenExchange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"secret-access","refresh_token":"secret-refresh",` +
+			`"expires_in":3600,"token_type":"bearer","account_username":"woto"}`))
+	}))
+	cassettePath := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder, err := wotoImgurtest.NewRecorder(cassettePath, wotoImgurtest.ModeRecord, server.Client().Transport)
+	if err != nil {
+		server.Close()
+		t.Error("when tried to get new recorder: ", err.Error())
+		return
+	}
+
+	config := &wotoImgur.ClientConfig{
+		HTTPClient:   recorder.Client(),
+		ClientSecret: "secret-client-secret",
+		TokenURL:     server.URL + "/oauth2/token",
+	}
+	client, err := wotoImgur.NewImgurClient("secret-client-id", config)
+	if err != nil {
+		server.Close()
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	_, err = client.ExchangeCode("secret-code")
+	server.Close()
+	if err != nil {
+		t.Error("when tried to exchange code: ", err.Error())
+		return
+	}
+
+	b, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Error("when tried to read cassette: ", err.Error())
+		return
+	}
+	for _, secret := range []string{"secret-client-id", "secret-client-secret", "secret-code", "secret-access", "secret-refresh"} {
+		if strings.Contains(string(b), secret) {
+			t.Error("cassette should not contain ", secret, ": ", string(b))
+		}
+	}
+
+	recorder, err = wotoImgurtest.NewRecorder(cassettePath, wotoImgurtest.ModeReplay, nil)
+	if err != nil {
+		t.Error("when tried to get replaying recorder: ", err.Error())
+		return
+	}
+
+	config.HTTPClient = recorder.Client()
+	client, err = wotoImgur.NewImgurClient("other-client-id", config)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	token, err := client.ExchangeCode("other-code")
+	if err != nil || token.AccountUsername != "woto" {
+		t.Error("redacted fields should still match on replay: ", token, err)
+	}
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+const commentTreeJSON = `[{"id":1,"comment":"root","children":[` +
+	`{"id":2,"parent_id":1,"comment":"reply","children":[{"id":3,"parent_id":2,"comment":"nested","children":[]}]},` +
+	`{"id":4,"parent_id":1,"comment":"second reply","children":[]}]},` +
+	`{"id":5,"comment":"another root","children":[]}]`
+
+func TestComments(t *testing.T) {
+	votes := map[string]bool{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/gallery/abc/comments/new", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":` + commentTreeJSON + `,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment/1/replies", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":1,"children":[{"id":2}]},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("image_id") != "abc" || r.FormValue("comment") != "nice" {
+			t.Error("unexpected comment form: ", r.Form.Encode())
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":10},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"data":{"id":1,"comment":"root"},"success":true,"status":200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":11},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment/10/vote/up", func(w http.ResponseWriter, r *http.Request) {
+		votes["up"] = true
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment/10", func(w http.ResponseWriter, r *http.Request) {
+		votes[r.Method] = true
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	comments, err := client.GetGalleryComments("abc", wotoImgur.CommentSortNew)
+	if err != nil || len(comments) != 2 {
+		t.Error("unexpected comments: ", comments, err)
+		return
+	}
+
+	if comments[0].CountReplies() != 3 {
+		t.Error("unexpected reply count: ", comments[0].CountReplies())
+	}
+
+	flat := wotoImgur.FlattenComments(comments)
+	order := []int{1, 2, 3, 4, 5}
+	if len(flat) != len(order) {
+		t.Error("unexpected flattened comments: ", flat)
+		return
+	}
+	for i, id := range order {
+		if flat[i].ID != id {
+			t.Error("unexpected flattened order at ", i, ": ", flat[i].ID)
+		}
+	}
+
+	maxDepth := 0
+	comments[0].Walk(func(comment *wotoImgur.Comment, depth int) bool {
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		return comment.ID != 2
+	})
+	if maxDepth != 1 {
+		t.Error("walk should skip the replies of comment 2, max depth: ", maxDepth)
+	}
+
+	comment, err := client.GetComment(1)
+	if err != nil || comment.Comment != "root" {
+		t.Error("unexpected comment: ", comment, err)
+	}
+
+	replies, err := client.GetCommentReplies(1)
+	if err != nil || len(replies.Children) != 1 {
+		t.Error("unexpected replies: ", replies, err)
+	}
+
+	if _, err = client.PostComment("abc", "nice"); err == nil {
+		t.Error("commenting should require authentication")
+	}
+
+	_ = client.SetToken(&wotoImgur.OAuthToken{AccessToken: "access"})
+
+	id, err := client.PostComment("abc", "nice")
+	if err != nil || id != 10 {
+		t.Error("unexpected posted comment: ", id, err)
+	}
+
+	id, err = client.ReplyToComment(1, "abc", "nice")
+	if err != nil || id != 11 {
+		t.Error("unexpected reply: ", id, err)
+	}
+
+	if err = client.VoteComment(10, wotoImgur.VoteUp); err != nil {
+		t.Error("when tried to vote: ", err.Error())
+	}
+
+	if err = client.VoteComment(10, "sideways"); err == nil {
+		t.Error("expected an error for an invalid vote")
+	}
+
+	if err = client.DeleteComment(10); err != nil {
+		t.Error("when tried to delete comment: ", err.Error())
+	}
+
+	if !votes["up"] || !votes[http.MethodDelete] {
+		t.Error("vote or delete request hasn't been sent: ", votes)
+	}
+}
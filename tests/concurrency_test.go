@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestConcurrentRequests(t *testing.T) {
+	var remaining int64 = 1000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-UserLimit", "1000")
+		w.Header().Set("X-RateLimit-UserRemaining", strconv.FormatInt(atomic.AddInt64(&remaining, -1), 10))
+		w.Header().Set("X-RateLimit-UserReset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.Header().Set("X-RateLimit-ClientLimit", "1000")
+		w.Header().Set("X-RateLimit-ClientRemaining", "500")
+		_, _ = w.Write([]byte(`{"data":{"id":"img"},"success":true,"status":200}`))
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:      newTestHTTPClient(t, server),
+		RateLimitPolicy: &wotoImgur.RateLimitPolicy{MinUserRemaining: 1},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if _, err := client.GetImageInfo("img"); err != nil {
+					t.Error("when tried to get image info: ", err.Error())
+					return
+				}
+
+				rl, _ := client.GetLastRateLimit()
+				if rl == nil || rl.ClientRemaining != 500 {
+					t.Error("unexpected last rate limit: ", rl)
+					return
+				}
+				// the snapshot belongs to the caller.
+				rl.UserRemaining = -1
+			}
+		}()
+	}
+	wg.Wait()
+
+	rl, _ := client.GetLastRateLimit()
+	if rl == nil || rl.UserRemaining < 0 {
+		t.Error("last rate limit has been modified through a snapshot: ", rl)
+	}
+}
+
+func TestConcurrentTokenRefresh(t *testing.T) {
+	var refreshes int32
+	server := newRefreshServer(t, &refreshes)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		TokenURL:   server.URL + "/oauth2/token",
+		Token:      &wotoImgur.OAuthToken{AccessToken: "old", RefreshToken: "refresh"},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetImageInfo("abc"); err != nil {
+				t.Error("when tried to get image info: ", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&refreshes) != 1 {
+		t.Error("the access token should be refreshed only once, got: ", refreshes)
+	}
+}
+
+func TestStructLiteralClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Client-ID client-id" {
+			t.Error("unexpected authorization: ", r.Header.Get("Authorization"))
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"img"},"success":true,"status":200}`))
+	}))
+	defer server.Close()
+
+	client := &wotoImgur.ImgurClient{
+		HTTPClient:    newTestHTTPClient(t, server),
+		ImgurClientID: "client-id",
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if info, err := client.GetImageInfo("img"); err != nil || info.ID != "img" {
+				t.Error("unexpected image info: ", info, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if client.IsAuthenticated() {
+		t.Error("struct literal client should be anonymous")
+	}
+}
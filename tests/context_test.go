@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.GetImageInfoCtx(ctx, "abc")
+	if err == nil {
+		t.Error("expected an error when the deadline is exceeded")
+		return
+	}
+
+	var imgurErr *wotoImgur.ImgurError
+	if !errors.As(err, &imgurErr) || !imgurErr.IsCanceled() {
+		t.Error("expected a canceled ImgurError, got: ", err.Error())
+	}
+}
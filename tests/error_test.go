@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestErrorKinds(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/image/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"data":{"error":"Unable to find an image with the id, missing",` +
+			`"request":"/3/image/missing","method":"GET"},"success":false,"status":404}`))
+	})
+	mux.HandleFunc("/3/image/limited", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"data":{"error":{"code":429,"message":"Too many requests"}},` +
+			`"success":false,"status":429}`))
+	})
+	mux.HandleFunc("/3/image/invalid", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"data":{"error":"Invalid id","request":"/3/image/invalid",` +
+			`"method":"GET"},"success":false,"status":400}`))
+	})
+	mux.HandleFunc("/3/image/empty", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/image/empty", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/album/empty", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":null,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/image/broken", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	_, err = client.GetImageInfo("missing")
+	if !errors.Is(err, wotoImgur.ErrNotFound) || errors.Is(err, wotoImgur.ErrServerError) {
+		t.Error("expected a not found error, got: ", err)
+	}
+
+	var imgurErr *wotoImgur.ImgurError
+	if !errors.As(err, &imgurErr) || imgurErr.Status != http.StatusNotFound || imgurErr.Payload == nil ||
+		imgurErr.Payload.Error != "Unable to find an image with the id, missing" || imgurErr.Payload.Method != "GET" {
+		t.Error("unexpected error details: ", imgurErr)
+	}
+
+	_, err = client.GetImageInfo("limited")
+	if !errors.As(err, &imgurErr) || !errors.Is(err, wotoImgur.ErrRateLimited) ||
+		imgurErr.Payload == nil || imgurErr.Payload.Error != "Too many requests" {
+		t.Error("expected a rate limited error, got: ", err)
+	}
+
+	_, err = client.GetImageInfo("invalid")
+	if !errors.Is(err, wotoImgur.ErrBadRequest) || errors.Is(err, wotoImgur.ErrValidation) {
+		t.Error("expected a bad request error, got: ", err)
+	}
+
+	if _, err = client.GetImageInfo("empty"); !errors.Is(err, wotoImgur.ErrDecode) {
+		t.Error("expected a decode error for null image data, got: ", err)
+	}
+
+	if _, err = client.GetGalleryImageInfo("empty"); !errors.Is(err, wotoImgur.ErrDecode) {
+		t.Error("expected a decode error for null gallery image data, got: ", err)
+	}
+
+	if _, err = client.GetGalleryAlbumInfo("empty"); !errors.Is(err, wotoImgur.ErrDecode) {
+		t.Error("expected a decode error for null gallery album data, got: ", err)
+	}
+
+	if _, err = client.GetImageInfo("broken"); !errors.Is(err, wotoImgur.ErrDecode) {
+		t.Error("expected a decode error, got: ", err)
+	}
+
+	if _, err = client.SearchGallery(wotoImgur.NewSearchQuery("")); !errors.Is(err, wotoImgur.ErrValidation) {
+		t.Error("expected a validation error, got: ", err)
+	}
+
+	if _, err = client.GetAccountSettings(); !errors.Is(err, wotoImgur.ErrUnauthorized) {
+		t.Error("expected an unauthorized error, got: ", err)
+	}
+}
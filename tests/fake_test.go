@@ -0,0 +1,221 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+	"github.com/ALiwoto/wotoImgur/wotoImgurtest"
+)
+
+func TestFakeImagesAndAlbums(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	uploaded, err := client.UploadImageFromReader(bytes.NewReader([]byte("content")),
+		&wotoImgur.UploadOptions{Name: "a.jpg", Title: "woto"})
+	if err != nil || uploaded.DeleteHash == "" || uploaded.MimeType != "image/jpeg" {
+		t.Error("unexpected uploaded image: ", uploaded, err)
+		return
+	}
+
+	if data, _ := server.ImageData(uploaded.ID); string(data) != "content" {
+		t.Error("unexpected stored content: ", string(data))
+	}
+
+	info, err := client.GetImageInfo(uploaded.ID)
+	if err != nil || info.Title != "woto" || info.DeleteHash != "" {
+		t.Error("deletehash should only be returned on upload: ", info, err)
+	}
+
+	if err = client.UpdateImageInfo(uploaded.ID, "changed", ""); !errors.Is(err, wotoImgur.ErrForbidden) {
+		t.Error("anonymous images shouldn't be modified by their id: ", err)
+	}
+
+	if err = client.UpdateImageInfo(uploaded.DeleteHash, "changed", ""); err != nil {
+		t.Error("when tried to update image by deletehash: ", err.Error())
+	}
+
+	album, err := client.CreateAlbum(&wotoImgur.AlbumOptions{
+		Title:        "album",
+		DeleteHashes: []string{uploaded.DeleteHash},
+	})
+	if err != nil || album.DeleteHash == "" {
+		t.Error("unexpected created album: ", album, err)
+		return
+	}
+
+	second := server.AddImage(wotoImgur.ImageInfo{Title: "second"}, nil)
+	if err = client.AddImagesToAlbum(album.DeleteHash, []string{second.ID}); err != nil {
+		t.Error("when tried to add images to album: ", err.Error())
+	}
+
+	images, err := client.GetAlbumImages(album.ID)
+	if err != nil || len(images) != 2 || images[0].Title != "changed" {
+		t.Error("unexpected album images: ", images, err)
+	}
+
+	if err = client.DeleteAlbum(album.ID); !errors.Is(err, wotoImgur.ErrForbidden) {
+		t.Error("anonymous albums shouldn't be deleted by their id: ", err)
+	}
+
+	if err = client.DeleteImage(uploaded.DeleteHash); err != nil {
+		t.Error("when tried to delete image: ", err.Error())
+	}
+
+	if _, err = client.GetImageInfo(uploaded.ID); !errors.Is(err, wotoImgur.ErrNotFound) {
+		t.Error("deleted image shouldn't be found: ", err)
+	}
+
+	if stored, _ := server.Album(album.ID); stored == nil || stored.ImagesCount != 1 {
+		t.Error("deleted image should be removed from the album: ", stored)
+	}
+}
+
+func TestFakeGallery(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		Token: &wotoImgur.OAuthToken{AccessToken: "access"},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	cat := server.AddImage(wotoImgur.ImageInfo{}, nil)
+	server.AddToGallery(cat.ID, "cute cat", "cats")
+
+	uploaded, err := client.UploadImage([]byte("aGVsbG8="), "", "base64", "", "")
+	if err != nil {
+		t.Error("when tried to upload image: ", err.Error())
+		return
+	}
+
+	item, err := client.SubmitToGallery(uploaded.ID, "funny dog", "", true, false, []string{"dogs"})
+	if err != nil || item.GetID() != uploaded.ID || item.IsAlbum() {
+		t.Error("unexpected submitted item: ", item, err)
+	}
+
+	if _, err = client.SubmitToGallery(cat.ID, "mine", "", true, false, nil); !errors.Is(err, wotoImgur.ErrForbidden) {
+		t.Error("only own images should be submitted: ", err)
+	}
+
+	if err = client.VoteGallery(uploaded.ID, wotoImgur.VoteUp); err != nil {
+		t.Error("when tried to vote: ", err.Error())
+	}
+
+	items, err := client.GetGallery(wotoImgur.GallerySectionHot, wotoImgur.GallerySortViral, "", 0, true, false, false)
+	if err != nil || len(items) != 2 || items[0].GetID() != uploaded.ID || items[0].Image.Ups != 1 {
+		t.Error("unexpected gallery: ", items, err)
+	}
+
+	found, err := client.SearchGallery(wotoImgur.NewSearchQuery("cat"))
+	if err != nil || len(found) != 1 || found[0].GetID() != cat.ID {
+		t.Error("unexpected search results: ", found, err)
+	}
+
+	if err = client.RemoveFromGallery(uploaded.ID); err != nil || server.InGallery(uploaded.ID) {
+		t.Error("item should be removed from the gallery: ", err)
+	}
+}
+
+func TestFakeRateLimits(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	img := server.AddImage(wotoImgur.ImageInfo{}, nil)
+	info, err := client.GetImageInfo(img.ID)
+	if err != nil || info.Limit == nil || info.Limit.UserLimit != wotoImgurtest.DefaultUserLimit ||
+		info.Limit.UserRemaining != wotoImgurtest.DefaultUserLimit-1 {
+		t.Error("unexpected rate limit: ", info, err)
+	}
+
+	uploaded, err := client.UploadImage([]byte("aGVsbG8="), "", "base64", "", "")
+	if err != nil || uploaded.Limit == nil || uploaded.Limit.PostRemaining != wotoImgurtest.DefaultPostLimit-1 {
+		t.Error("unexpected post rate limit: ", uploaded, err)
+	}
+
+	server.SetCredits(0, 100, 100)
+	if _, err = client.GetImageInfo(img.ID); !errors.Is(err, wotoImgur.ErrRateLimited) {
+		t.Error("requests should fail once the credits are exhausted: ", err)
+	}
+
+	server.ResetCredits()
+	if _, err = client.GetImageInfo(img.ID); err != nil {
+		t.Error("when tried to get image info after reset: ", err.Error())
+	}
+}
+
+func TestFakeVideoUpload(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	uploaded, err := client.UploadVideo(bytes.NewReader([]byte("video")), &wotoImgur.UploadOptions{
+		Name:              "a.mp4",
+		ProcessingTimeout: time.Second,
+	})
+	if err != nil {
+		t.Error("when tried to upload video: ", err.Error())
+		return
+	}
+
+	if uploaded.MimeType != "video/mp4" || uploaded.Mp4 != "https://i.imgur.com/"+uploaded.ID+".mp4" ||
+		uploaded.Gifv == "" {
+		t.Error("unexpected uploaded video: ", uploaded)
+	}
+}
+
+func TestFakeLastRateLimit(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	img := server.AddImage(wotoImgur.ImageInfo{}, nil)
+	album := server.AddAlbum(wotoImgur.AlbumInfo{}, img.ID)
+	server.AddToGallery(img.ID, "cat", "cats")
+	server.AddToGallery(album.ID, "cats", "cats")
+
+	calls := []func() error{
+		func() error { _, err := client.GetAlbumInfo(album.ID); return err },
+		func() error { _, err := client.GetGalleryAlbumInfo(album.ID); return err },
+		func() error { _, err := client.GetGalleryImageInfo(img.ID); return err },
+	}
+	for i, call := range calls {
+		if err = call(); err != nil {
+			t.Error("when tried to send request ", i, ": ", err.Error())
+			continue
+		}
+
+		rl, _ := client.GetLastRateLimit()
+		if rl == nil || rl.UserRemaining != int64(wotoImgurtest.DefaultUserLimit-i-1) {
+			t.Error("last rate limit is stale after request ", i, ": ", rl)
+		}
+	}
+}
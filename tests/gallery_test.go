@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestGallery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/3/gallery/top/time/week/0":
+			query := r.URL.Query()
+			if query.Get("showViral") != "false" || query.Get("mature") != "true" || query.Get("album_previews") != "true" {
+				t.Error("unexpected gallery query: ", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"img","is_album":false,"ups":3},` +
+				`{"id":"alb","is_album":true,"images_count":2,"images":[{"id":"a"},{"id":"b"}]}],` +
+				`"success":true,"status":200}`))
+		case "/3/gallery/hot/viral/day/0":
+			_, _ = w.Write([]byte(`{"data":[{"id":"hot"}],"success":true,"status":200}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[],"success":true,"status":200}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	items, err := client.GetGallery(wotoImgur.GallerySectionTop, wotoImgur.GallerySortTime,
+		wotoImgur.GalleryWindowWeek, 0, false, true, true)
+	if err != nil {
+		t.Error("when tried to get gallery: ", err.Error())
+		return
+	}
+
+	if len(items) != 2 || items[0].IsAlbum() || items[0].Image.Ups != 3 {
+		t.Error("first item should be an image: ", items)
+		return
+	}
+
+	if !items[1].IsAlbum() || len(items[1].Album.Images) != 2 || items[1].GetID() != "alb" {
+		t.Error("second item should be an album: ", items[1])
+	}
+
+	if info := items[1].ToGenericInfo(); info.GAlbum == nil || info.GImage != nil {
+		t.Error("unexpected generic info: ", info)
+	}
+
+	all, err := client.GalleryPager("", "", "", true, false, false, 0).All(context.Background())
+	if err != nil || len(all) != 1 || all[0].GetID() != "hot" {
+		t.Error("unexpected default gallery: ", all, err)
+	}
+}
+
+func TestSearchGallery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/3/gallery/search/top/week/2" {
+			_, _ = w.Write([]byte(`{"data":[],"success":true,"status":200}`))
+			return
+		}
+
+		query := r.URL.Query()
+		if query.Get("q") != "cats" || query.Get("q_all") != "cute fluffy" || query.Get("q_not") != "dogs" ||
+			query.Get("q_exactly") != "so soft" || query.Get("q_type") != "anigif" || query.Get("q_size_px") != "med" {
+			t.Error("unexpected search query: ", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"cat","is_album":false},{"id":"cats","is_album":true}],` +
+			`"success":true,"status":200}`))
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.SearchGallery(wotoImgur.NewSearchQuery("")); err == nil {
+		t.Error("expected an error for an empty search query")
+	}
+
+	query := wotoImgur.NewSearchQuery("cats").
+		All("cute", "fluffy").
+		Not("dogs").
+		Exactly("so soft").
+		Type(wotoImgur.SearchTypeAniGIF).
+		Size(wotoImgur.SearchSizeMedium).
+		Sort(wotoImgur.GallerySortTop).
+		Window(wotoImgur.GalleryWindowWeek).
+		Page(2)
+
+	items, err := client.SearchGallery(query)
+	if err != nil || len(items) != 2 || !items[1].IsAlbum() {
+		t.Error("unexpected search results: ", items, err)
+	}
+
+	all, err := client.SearchGalleryPager(query, 0).All(context.Background())
+	if err != nil || len(all) != 2 {
+		t.Error("unexpected paged search results: ", all, err)
+	}
+}
+
+func TestGallerySubmission(t *testing.T) {
+	inGallery := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/gallery/alb", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			if r.FormValue("title") != "woto" || r.FormValue("terms") != "1" || r.FormValue("tags") != "cats,cute" {
+				t.Error("unexpected submit form: ", r.Form.Encode())
+			}
+			inGallery = true
+		case http.MethodDelete:
+			inGallery = false
+		default:
+			if !inGallery {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"id":"alb","title":"woto","is_album":true},"success":true,"status":200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/alb/vote/down", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/alb/votes", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"ups":5,"downs":1},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/album/alb/favorite", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":"favorited","success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		Token:      &wotoImgur.OAuthToken{AccessToken: "access"},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	item, err := client.SubmitToGallery("alb", "woto", "", true, false, []string{"cats", "cute"})
+	if err != nil || !item.IsAlbum() || item.Album.Title != "woto" {
+		t.Error("unexpected submitted item: ", item, err)
+	}
+
+	if err = client.VoteGallery("alb", wotoImgur.VoteDown); err != nil {
+		t.Error("when tried to vote: ", err.Error())
+	}
+
+	votes, err := client.GetGalleryVotes("alb")
+	if err != nil || votes.Ups != 5 || votes.Downs != 1 {
+		t.Error("unexpected votes: ", votes, err)
+	}
+
+	favorited, err := client.FavoriteAlbum("alb")
+	if err != nil || !favorited {
+		t.Error("album should've been favorited: ", err)
+	}
+
+	if err = client.RemoveFromGallery("alb"); err != nil {
+		t.Error("when tried to remove from gallery: ", err.Error())
+	}
+
+	if _, err = client.GetGalleryItem("alb"); err == nil {
+		t.Error("removed item shouldn't be found in the gallery")
+	}
+}
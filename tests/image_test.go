@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestImageManagement(t *testing.T) {
+	favorited := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/image/del", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+		case http.MethodPost:
+			if r.FormValue("title") != "new title" || r.Form.Has("description") {
+				t.Error("unexpected update form: ", r.Form.Encode())
+			}
+		default:
+			t.Error("unexpected method: ", r.Method)
+		}
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/image/abc/favorite", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		favorited = !favorited
+		state := "unfavorited"
+		if favorited {
+			state = "favorited"
+		}
+		_, _ = w.Write([]byte(`{"data":"` + state + `","success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if err = client.UpdateImageInfo("del", "new title", ""); err != nil {
+		t.Error("when tried to update image: ", err.Error())
+	}
+
+	if err = client.DeleteImage("del"); err != nil {
+		t.Error("when tried to delete image: ", err.Error())
+	}
+
+	if _, err = client.FavoriteImage("abc"); err == nil {
+		t.Error("favoriting should require an authenticated client")
+	}
+
+	_ = client.SetToken(&wotoImgur.OAuthToken{AccessToken: "access"})
+	isFavorite, err := client.FavoriteImage("abc")
+	if err != nil || !isFavorite {
+		t.Error("image should've been favorited: ", err)
+	}
+
+	isFavorite, err = client.FavoriteImage("abc")
+	if err != nil || isFavorite {
+		t.Error("image should've been unfavorited: ", err)
+	}
+}
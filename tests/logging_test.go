@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+	"github.com/ALiwoto/wotoImgur/wotoImgurtest"
+)
+
+func TestLogger(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	var output bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	token := &wotoImgur.OAuthToken{
+		AccessToken:     "secret-access",
+		RefreshToken:    "secret-refresh",
+		AccountUsername: "woto",
+	}
+	client, err := server.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		Token:       token,
+		RapidAPIKey: "secret-key",
+		Logger:      logger,
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	img := server.AddImage(wotoImgur.ImageInfo{}, nil)
+	if _, err = client.GetInfoFromURL("https://imgur.com/" + img.ID); err != nil {
+		t.Error("when tried to get info from url: ", err.Error())
+	}
+	logger.Info("current token", "token", token)
+
+	logs := output.String()
+	for _, expected := range []string{
+		`"msg":"detected imgur image url"`,
+		`"path":"/3/image/` + img.ID + `"`,
+		`"status":404`,
+		`"status":200`,
+		`"duration":`,
+		`"user_remaining":`,
+		`"account_username":"woto"`,
+	} {
+		if !strings.Contains(logs, expected) {
+			t.Error("logs should contain ", expected, ": ", logs)
+		}
+	}
+
+	for _, secret := range []string{"secret-access", "secret-refresh", "secret-key"} {
+		if strings.Contains(logs, secret) {
+			t.Error("logs should not contain ", secret, ": ", logs)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestMiddlewares(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-RapidAPI-Key") != "rapid-key" ||
+			r.Header.Get("X-RapidAPI-Host") != "imgur-apiv3.p.rapidapi.com" {
+			t.Error("missing RapidAPI headers for ", r.Method, " ", r.URL.Path, ": ", r.Header)
+		}
+		if r.Header.Get("X-Custom") != "woto" {
+			t.Error("missing header of the middleware for ", r.Method, " ", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"abc"},"success":true,"status":200}`))
+	}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) wotoImgur.Middleware {
+		return func(next wotoImgur.Handler) wotoImgur.Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" "+req.Method)
+				return next(req)
+			}
+		}
+	}
+	setHeader := func(next wotoImgur.Handler) wotoImgur.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Custom", "woto")
+			return next(req)
+		}
+	}
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:  http.DefaultClient,
+		RapidAPIKey: "rapid-key",
+		BaseURL:     server.URL + "/3/",
+		Middlewares: []wotoImgur.Middleware{trace("outer"), setHeader, trace("inner")},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.GetImageInfo("abc"); err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+	}
+
+	if _, err = client.UploadImageFromReader(bytes.NewReader([]byte("content")), nil); err != nil {
+		t.Error("when tried to upload image: ", err.Error())
+	}
+
+	expected := "outer GET, inner GET, outer POST, inner POST"
+	if strings.Join(calls, ", ") != expected {
+		t.Error("unexpected middleware calls: ", calls)
+	}
+}
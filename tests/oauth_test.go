@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+// rewriteTransport sends every request to the given test server,
+// regardless of the host it was originally created for.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestHTTPClient(t *testing.T, server *httptest.Server) *http.Client {
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal("when tried to parse test server url: ", err.Error())
+	}
+
+	return &http.Client{Transport: &rewriteTransport{target: target}}
+}
+
+func TestOAuthCodeExchange(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error("when tried to parse token form: ", err.Error())
+		}
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "the-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"data":{"error":"invalid grant"},"success":false,"status":400}`))
+			return
+		}
+		if r.Form.Get("client_id") != "client-id" || r.Form.Get("client_secret") != "client-secret" {
+			t.Error("unexpected client credentials: ", r.Form.Encode())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access","refresh_token":"refresh",` +
+			`"expires_in":3600,"token_type":"bearer","account_id":42,"account_username":"woto"}`))
+	})
+	mux.HandleFunc("/3/image/abc", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer access" {
+			t.Error("unexpected authorization header: ", auth)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"abc"},"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:   newTestHTTPClient(t, server),
+		ClientSecret: "client-secret",
+		TokenURL:     server.URL + "/oauth2/token",
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	authURL := client.GetAuthorizeURL(wotoImgur.ResponseTypeCode, "xyz")
+	if !strings.Contains(authURL, "response_type=code") || !strings.Contains(authURL, "state=xyz") {
+		t.Error("unexpected authorize url: ", authURL)
+	}
+
+	if _, err = client.ExchangeCode("wrong-code"); err == nil {
+		t.Error("expected an error when exchanging an invalid code")
+		return
+	}
+
+	token, err := client.ExchangeCode("the-code")
+	if err != nil {
+		t.Error("when tried to exchange code: ", err.Error())
+		return
+	}
+
+	if token.AccessToken != "access" || token.AccountUsername != "woto" || token.IsExpired() {
+		t.Error("unexpected token: ", token)
+		return
+	}
+
+	if !client.IsAuthenticated() {
+		t.Error("client should be authenticated after exchanging a code")
+		return
+	}
+
+	if _, err = client.GetImageInfo("abc"); err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+	}
+}
+
+func TestParseTokenFromRedirectURL(t *testing.T) {
+	token, err := wotoImgur.ParseTokenFromRedirectURL("https://example.com/cb#access_token=a" +
+		"&expires_in=10&token_type=bearer&refresh_token=r&account_username=woto&account_id=7")
+	if err != nil {
+		t.Error("when tried to parse redirect url: ", err.Error())
+		return
+	}
+
+	if token.AccessToken != "a" || token.RefreshToken != "r" || token.AccountID != 7 {
+		t.Error("unexpected token: ", token)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestPager(t *testing.T) {
+	pages := [][]int{{1, 2, 3}, {4, 5}, {}}
+	fetched := 0
+	fetch := func(ctx context.Context, page int) ([]int, *wotoImgur.RateLimit, error) {
+		fetched++
+		return pages[page], &wotoImgur.RateLimit{ClientRemaining: int64(100 - page)}, nil
+	}
+
+	all, err := wotoImgur.NewPager(fetch, 0).All(context.Background())
+	if err != nil || len(all) != 5 || all[4] != 5 {
+		t.Error("unexpected items: ", all, err)
+	}
+
+	fetched = 0
+	pager := wotoImgur.NewPager(fetch, 2)
+	limited, _ := pager.All(context.Background())
+	if len(limited) != 2 || fetched != 1 {
+		t.Error("pager should stop at the item limit without fetching more pages: ", limited, fetched)
+	}
+
+	if pager.RateLimit() == nil || pager.RateLimit().ClientRemaining != 100 {
+		t.Error("rate limit of the last page should be exposed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pager = wotoImgur.NewPager(fetch, 0)
+	if pager.Next(ctx) {
+		t.Error("pager should stop when the context is canceled")
+	}
+
+	var imgurErr *wotoImgur.ImgurError
+	if !errors.As(pager.Err(), &imgurErr) || !imgurErr.IsCanceled() {
+		t.Error("expected a canceled error, got: ", pager.Err())
+	}
+}
+
+func TestAccountImagesPager(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/3/account/woto/images/") {
+		case "0":
+			_, _ = w.Write([]byte(`{"data":[{"id":"a"},{"id":"b"}],"success":true,"status":200}`))
+		case "1":
+			_, _ = w.Write([]byte(`{"data":[{"id":"c"}],"success":true,"status":200}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[],"success":true,"status":200}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	pager := client.AccountImagesPager("woto", 0)
+	images, err := pager.All(context.Background())
+	if err != nil || len(images) != 3 || images[2].ID != "c" {
+		t.Error("unexpected images: ", images, err)
+	}
+
+	if pager.Page() != 2 {
+		t.Error("unexpected page count: ", pager.Page())
+	}
+
+	if _, err = client.AccountAlbumsPager("", 0).All(context.Background()); err == nil {
+		t.Error("iterating the current account should require authentication")
+	}
+}
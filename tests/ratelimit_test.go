@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestRateLimitGovernor(t *testing.T) {
+	sent := 0
+	userRemaining := 5
+	reset := time.Now().Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent++
+		userRemaining--
+		w.Header().Set("X-RateLimit-UserLimit", "5")
+		w.Header().Set("X-RateLimit-UserRemaining", strconv.Itoa(userRemaining))
+		w.Header().Set("X-RateLimit-UserReset", strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("X-RateLimit-ClientLimit", "100")
+		w.Header().Set("X-RateLimit-ClientRemaining", "50")
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("X-Post-Rate-Limit-Limit", "10")
+			w.Header().Set("X-Post-Rate-Limit-Remaining", "0")
+			w.Header().Set("X-Post-Rate-Limit-Reset", "1")
+			_, _ = w.Write([]byte(`{"data":"favorited","success":true,"status":200}`))
+		case http.MethodDelete:
+			_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"id":"img"},"success":true,"status":200}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:      newTestHTTPClient(t, server),
+		Token:           &wotoImgur.OAuthToken{AccessToken: "access"},
+		RateLimitPolicy: &wotoImgur.RateLimitPolicy{MinUserRemaining: 1, MaxWait: time.Minute},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	info, err := client.GetImageInfo("img")
+	if err != nil || info.Limit == nil || info.Limit.UserRemaining != 4 {
+		t.Error("unexpected image info: ", info, err)
+	}
+
+	started := time.Now()
+	if err = client.DeleteImage("img"); err != nil {
+		t.Error("when tried to delete image: ", err.Error())
+	}
+
+	if _, err = client.FavoriteImage("img"); err != nil {
+		t.Error("when tried to favorite image: ", err.Error())
+	}
+
+	// the post limit has been used up, but resets within a second.
+	if _, err = client.FavoriteImage("img"); err != nil || time.Since(started) < 500*time.Millisecond {
+		t.Error("post request should've waited for the reset: ", time.Since(started), err)
+	}
+
+	sentBefore := sent
+	_, err = client.GetImageInfo("img")
+	if !errors.Is(err, wotoImgur.ErrRateLimited) || sent != sentBefore {
+		t.Error("request shouldn't be sent when credits are exhausted: ", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client, _ = wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:      newTestHTTPClient(t, server),
+		RateLimitPolicy: &wotoImgur.RateLimitPolicy{MinUserRemaining: 100},
+	})
+	_, _ = client.GetImageInfo("img")
+	if _, err = client.GetImageInfoCtx(ctx, "img"); !errors.Is(err, wotoImgur.ErrCanceled) {
+		t.Error("waiting for credits should respect the context: ", err)
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	attempts := map[string]int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/image/flaky", func(w http.ResponseWriter, r *http.Request) {
+		attempts["flaky"]++
+		if attempts["flaky"] < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"flaky"},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/image/gone", func(w http.ResponseWriter, r *http.Request) {
+		attempts["gone"]++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/3/image/down", func(w http.ResponseWriter, r *http.Request) {
+		attempts["down"]++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/3/image", func(w http.ResponseWriter, r *http.Request) {
+		attempts["upload"]++
+		b, _ := io.ReadAll(r.Body)
+		if attempts["upload"] == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if !strings.Contains(string(b), "replayed-content") {
+			t.Error("replayed upload body is incomplete: ", string(b))
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"uploaded"},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/comment", func(w http.ResponseWriter, r *http.Request) {
+		attempts["comment"]++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var retries []*wotoImgur.RetryInfo
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		Token:      &wotoImgur.OAuthToken{AccessToken: "access"},
+		RetryPolicy: &wotoImgur.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    5 * time.Millisecond,
+			Jitter:      0.5,
+			OnRetry: func(info *wotoImgur.RetryInfo) {
+				retries = append(retries, info)
+			},
+		},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	info, err := client.GetImageInfo("flaky")
+	if err != nil || info.ID != "flaky" || attempts["flaky"] != 3 {
+		t.Error("request should succeed on the third attempt: ", attempts["flaky"], err)
+	}
+
+	if len(retries) != 2 || retries[0].Attempt != 1 || retries[1].Status != http.StatusServiceUnavailable ||
+		retries[1].Delay > 5*time.Millisecond {
+		t.Error("unexpected retries: ", retries)
+	}
+
+	if _, err = client.GetImageInfo("gone"); err == nil || attempts["gone"] != 1 {
+		t.Error("not found errors shouldn't be retried: ", attempts["gone"])
+	}
+
+	if _, err = client.GetImageInfo("down"); err == nil || attempts["down"] != 3 {
+		t.Error("request should be given up after max attempts: ", attempts["down"])
+	}
+
+	opts := &wotoImgur.UploadOptions{Name: "a.png"}
+	uploaded, err := client.UploadImageFromReader(bytes.NewReader([]byte("replayed-content")), opts)
+	if err != nil || uploaded.ID != "uploaded" || attempts["upload"] != 2 {
+		t.Error("seekable upload should be replayed: ", attempts["upload"], err)
+	}
+
+	attempts["upload"] = 0
+	reader := io.MultiReader(strings.NewReader("replayed-content"))
+	if _, err = client.UploadImageFromReader(reader, opts); err == nil || attempts["upload"] != 1 {
+		t.Error("non-seekable upload shouldn't be retried: ", attempts["upload"])
+	}
+
+	if _, err = client.PostComment("abc", "nice"); err == nil || attempts["comment"] != 1 {
+		t.Error("posting a comment shouldn't be retried: ", attempts["comment"])
+	}
+}
+
+func TestRetryUnreadUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("woto"), 1<<20)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// answer before the body has been read at all.
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		f, _, err := r.FormFile("image")
+		if err != nil {
+			t.Error("when tried to read the uploaded file: ", err.Error())
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+
+		if b, _ := io.ReadAll(f); !bytes.Equal(b, content) {
+			t.Error("replayed upload is corrupted: ", len(b), " bytes")
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"uploaded"},"success":true,"status":200}`))
+	}))
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:  newTestHTTPClient(t, server),
+		RetryPolicy: &wotoImgur.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	uploaded, err := client.UploadImageWithOptions(content, "file", &wotoImgur.UploadOptions{Name: "a.png"})
+	if err != nil || uploaded.ID != "uploaded" || attempts != 2 {
+		t.Error("upload should succeed on the second attempt: ", attempts, err)
+	}
+}
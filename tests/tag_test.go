@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func TestTagsAndTopics(t *testing.T) {
+	requests := map[string]bool{}
+	mux := http.NewServeMux()
+	setRateLimit := func(w http.ResponseWriter, userRemaining string) {
+		w.Header().Set("X-RateLimit-UserLimit", "1000")
+		w.Header().Set("X-RateLimit-UserRemaining", userRemaining)
+		w.Header().Set("X-RateLimit-UserReset", "0")
+		w.Header().Set("X-RateLimit-ClientLimit", "1000")
+		w.Header().Set("X-RateLimit-ClientRemaining", "1000")
+	}
+	mux.HandleFunc("/3/topics/defaults", func(w http.ResponseWriter, r *http.Request) {
+		setRateLimit(w, "900")
+		_, _ = w.Write([]byte(`{"data":[{"id":2,"name":"Funny","topPost":{"id":"alb","is_album":true}}],` +
+			`"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/topics/2/top/month/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"img"}],"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/tags", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"tags":[{"name":"cats","followers":10}],"featured":"cats"},` +
+			`"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/t/cats/viral/week/0", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"name":"cats","total_items":2,"items":[{"id":"a"},{"id":"b","is_album":true}]},` +
+			`"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/img/tags", func(w http.ResponseWriter, r *http.Request) {
+		setRateLimit(w, "800")
+		_, _ = w.Write([]byte(`{"data":{"tags":[{"name":"cats","ups":3,"downs":1,"author":"woto"}]},` +
+			`"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/img/vote/tag/cats/up", func(w http.ResponseWriter, r *http.Request) {
+		requests["vote"] = true
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/gallery/tags/img", func(w http.ResponseWriter, r *http.Request) {
+		requests["tags="+r.FormValue("tags")] = true
+		_, _ = w.Write([]byte(`{"data":true,"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		Token:      &wotoImgur.OAuthToken{AccessToken: "access"},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	topics, err := client.GetDefaultTopics()
+	if err != nil || len(topics) != 1 || topics[0].TopPost == nil || !topics[0].TopPost.IsAlbum() {
+		t.Error("unexpected topics: ", topics, err)
+	}
+
+	// topics have no limit of their own, so it's only kept by the client.
+	if rl, _ := client.GetLastRateLimit(); rl == nil || rl.UserRemaining != 900 {
+		t.Error("unexpected rate limit of default topics: ", rl)
+	}
+
+	items, err := client.GetTopicGallery("2", wotoImgur.GallerySortTop, wotoImgur.GalleryWindowMonth, 1)
+	if err != nil || len(items) != 1 {
+		t.Error("unexpected topic gallery: ", items, err)
+	}
+
+	tags, err := client.GetTags()
+	if err != nil || len(tags.Tags) != 1 || tags.Featured != "cats" {
+		t.Error("unexpected tags: ", tags, err)
+	}
+
+	tag, err := client.GetTag("cats", "", "", 0)
+	if err != nil || tag.TotalItems != 2 || len(tag.Items) != 2 || !tag.Items[1].IsAlbum() {
+		t.Error("unexpected tag: ", tag, err)
+	}
+
+	votes, err := client.GetGalleryItemTags("img")
+	if err != nil || len(votes) != 1 || votes[0].Ups != 3 {
+		t.Error("unexpected tag votes: ", votes, err)
+	}
+
+	if rl, _ := client.GetLastRateLimit(); rl == nil || rl.UserRemaining != 800 {
+		t.Error("unexpected rate limit of tag votes: ", rl)
+	}
+
+	if err = client.VoteTag("img", "cats", wotoImgur.VoteVeto); err == nil {
+		t.Error("veto shouldn't be accepted for tag votes")
+	}
+
+	if err = client.VoteTag("img", "cats", wotoImgur.VoteUp); err != nil {
+		t.Error("when tried to vote on tag: ", err.Error())
+	}
+
+	if err = client.UpdateGalleryTags("img", []string{"cats", "cute"}); err != nil {
+		t.Error("when tried to update tags: ", err.Error())
+	}
+
+	if !requests["vote"] || !requests["tags=cats,cute"] {
+		t.Error("tag requests haven't been sent as expected: ", requests)
+	}
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+func newRefreshServer(t *testing.T, refreshes *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error("when tried to parse token form: ", err.Error())
+		}
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"data":{"error":"invalid refresh token"},"success":false,"status":400}`))
+			return
+		}
+
+		atomic.AddInt32(refreshes, 1)
+		_, _ = w.Write([]byte(`{"access_token":"new","expires_in":3600,"token_type":"bearer"}`))
+	})
+	mux.HandleFunc("/3/image", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"data":{"error":"expired"},"success":false,"status":401}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"uploaded"},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/image/abc", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"data":{"error":"expired"},"success":false,"status":401}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"abc"},"success":true,"status":200}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	var refreshes int32
+	server := newRefreshServer(t, &refreshes)
+	defer server.Close()
+
+	source, err := wotoImgur.NewFileTokenSource(filepath.Join(t.TempDir(), "token.json"))
+	if err != nil {
+		t.Error("when tried to create file token source: ", err.Error())
+		return
+	}
+
+	var rotated *wotoImgur.OAuthToken
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient:    newTestHTTPClient(t, server),
+		TokenURL:      server.URL + "/oauth2/token",
+		TokenSource:   source,
+		Token:         &wotoImgur.OAuthToken{AccessToken: "old", RefreshToken: "refresh"},
+		OnTokenRotate: func(token *wotoImgur.OAuthToken) { rotated = token },
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.GetImageInfo("abc"); err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+		return
+	}
+
+	if refreshes != 1 || rotated == nil || rotated.AccessToken != "new" {
+		t.Error("token has not been rotated as expected")
+		return
+	}
+
+	if rotated.RefreshToken != "refresh" {
+		t.Error("refresh token should be kept when imgur doesn't return a new one")
+	}
+
+	// the token should've been persisted to the file.
+	reloaded, err := wotoImgur.NewFileTokenSource(source.Path())
+	if err != nil {
+		t.Error("when tried to reload file token source: ", err.Error())
+		return
+	}
+
+	token, _ := reloaded.Token()
+	if token == nil || token.AccessToken != "new" {
+		t.Error("token has not been persisted to the file")
+	}
+}
+
+func TestTokenRefreshOnUnauthorizedUpload(t *testing.T) {
+	var refreshes int32
+	server := newRefreshServer(t, &refreshes)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		TokenURL:   server.URL + "/oauth2/token",
+		Token:      &wotoImgur.OAuthToken{AccessToken: "old", RefreshToken: "refresh"},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	// a non-seekable body can't be sent again, so the 401 is returned as is.
+	reader := io.MultiReader(strings.NewReader("content"))
+	_, err = client.UploadImageFromReader(reader, &wotoImgur.UploadOptions{Name: "a.png"})
+	if !errors.Is(err, wotoImgur.ErrUnauthorized) || refreshes != 1 {
+		t.Error("upload should fail with the original 401: ", refreshes, err)
+	}
+
+	if token := client.GetToken(); token == nil || token.AccessToken != "new" {
+		t.Error("token should be refreshed for the next requests: ", token)
+	}
+
+	uploaded, err := client.UploadImageFromReader(io.MultiReader(strings.NewReader("content")), nil)
+	if err != nil || uploaded.ID != "uploaded" {
+		t.Error("next upload should use the refreshed token: ", uploaded, err)
+	}
+}
+
+func TestTokenRefreshOnExpiry(t *testing.T) {
+	var refreshes int32
+	server := newRefreshServer(t, &refreshes)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		TokenURL:   server.URL + "/oauth2/token",
+		Token: &wotoImgur.OAuthToken{
+			AccessToken:  "old",
+			RefreshToken: "refresh",
+			Expiry:       time.Now().Add(-time.Hour),
+		},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.GetImageInfo("abc"); err != nil {
+		t.Error("when tried to get image info: ", err.Error())
+		return
+	}
+
+	if refreshes != 1 {
+		t.Error("expired token should've been refreshed before the request, refreshes: ", refreshes)
+	}
+}
+
+func TestTokenRotateCallbackUsesClient(t *testing.T) {
+	var refreshes int32
+	server := newRefreshServer(t, &refreshes)
+	defer server.Close()
+
+	var client *wotoImgur.ImgurClient
+	var callbackErr error
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+		TokenURL:   server.URL + "/oauth2/token",
+		Token:      &wotoImgur.OAuthToken{AccessToken: "old", RefreshToken: "refresh"},
+		OnTokenRotate: func(token *wotoImgur.OAuthToken) {
+			if _, callbackErr = client.GetImageInfo("abc"); callbackErr == nil {
+				callbackErr = client.SetToken(token)
+			}
+		},
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.RefreshAccessToken()
+		done <- err
+	}()
+
+	select {
+	case err = <-done:
+		if err != nil || callbackErr != nil {
+			t.Error("when tried to use the client from the callback: ", err, callbackErr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("using the client from the rotate callback deadlocked")
+	}
+}
@@ -1,19 +1,38 @@
 package tests
 
 import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ALiwoto/wotoImgur/wotoImgur"
+	"github.com/ALiwoto/wotoImgur/wotoImgurtest"
 )
 
 func TestUploadFile(t *testing.T) {
-	client, err := wotoImgur.NewImgurClient("", nil)
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
 	if err != nil {
 		t.Error("when tried to get new client: ", err.Error())
 		return
 	}
 
-	info, err := client.UploadImageFromFile("temp.png", "", "file title", "file description")
+	filename := filepath.Join(t.TempDir(), "temp.png")
+	if err = writeTestPNG(filename, 4, 3); err != nil {
+		t.Error("when tried to create temp.png: ", err.Error())
+		return
+	}
+
+	info, err := client.UploadImageFromFile(filename, "", "file title", "file description")
 	if err != nil {
 		t.Error("when tried to upload new photo: ", err.Error())
 		return
@@ -23,4 +42,142 @@ func TestUploadFile(t *testing.T) {
 		t.Error("link of the uploaded image is empty")
 		return
 	}
+
+	stored, ok := server.Image(info.ID)
+	if !ok || stored.Title != "file title" || stored.Width != 4 || stored.Height != 3 || stored.MimeType != "image/png" {
+		t.Error("unexpected stored image: ", stored)
+	}
+}
+
+// writeTestPNG writes a blank png image with the given size to the file.
+func writeTestPNG(filename string, width, height int) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height)))
+}
+
+func newMultipartServer(t *testing.T, expected []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Error("when tried to read multipart body: ", err.Error())
+			return
+		}
+
+		fields := map[string]string{}
+		var content []byte
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Error("when tried to read next part: ", err.Error())
+				return
+			}
+
+			b, _ := io.ReadAll(part)
+			if part.FormName() == "image" {
+				content = b
+				fields["filename"] = part.FileName()
+				continue
+			}
+			fields[part.FormName()] = string(b)
+		}
+
+		if !bytes.Equal(content, expected) {
+			t.Error("uploaded content doesn't match, got ", len(content), " bytes")
+		}
+		if fields["type"] != "file" || fields["title"] != "file title" {
+			t.Error("unexpected upload fields: ", fields)
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"id":"abc","link":"https://i.imgur.com/abc.png","name":"` +
+			fields["filename"] + `"},"success":true,"status":200}`))
+	}))
+}
+
+func TestUploadMultipartStream(t *testing.T) {
+	content := bytes.Repeat([]byte("woto"), 64*1024)
+	server := newMultipartServer(t, content)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	// io.MultiReader hides the underlying bytes.Reader, so nothing can be
+	// buffered or rewound.
+	info, err := client.UploadImageFromReader(io.MultiReader(bytes.NewReader(content)), &wotoImgur.UploadOptions{
+		Title: "file title",
+		Name:  "stream.png",
+	})
+	if err != nil {
+		t.Error("when tried to upload from reader: ", err.Error())
+		return
+	}
+
+	if info.Name != "stream.png" {
+		t.Error("unexpected file name: ", info.Name)
+	}
+
+	filename := filepath.Join(t.TempDir(), "temp.png")
+	if err = os.WriteFile(filename, content, 0600); err != nil {
+		t.Error("when tried to write temp file: ", err.Error())
+		return
+	}
+
+	info, err = client.UploadImageFromFile(filename, "", "file title", "file description")
+	if err != nil {
+		t.Error("when tried to upload from file: ", err.Error())
+		return
+	}
+
+	if info.Name != "temp.png" {
+		t.Error("unexpected file name: ", info.Name)
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	content := bytes.Repeat([]byte("woto"), 256*1024)
+	server := newMultipartServer(t, content)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	var events []wotoImgur.UploadProgress
+	_, err = client.UploadImageWithOptions(content, "file", &wotoImgur.UploadOptions{
+		Title:            "file title",
+		ProgressInterval: time.Nanosecond,
+		OnProgress: func(progress wotoImgur.UploadProgress) {
+			events = append(events, progress)
+		},
+	})
+	if err != nil {
+		t.Error("when tried to upload with progress: ", err.Error())
+		return
+	}
+
+	if len(events) < 2 {
+		t.Error("expected several progress events, got ", len(events))
+		return
+	}
+
+	last := events[len(events)-1]
+	if !last.Done || last.BytesSent != int64(len(content)) || last.Percent() != 100 {
+		t.Error("unexpected last progress event: ", last)
+	}
 }
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+	"github.com/ALiwoto/wotoImgur/wotoImgurtest"
+)
+
+func TestUploadVideo(t *testing.T) {
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/upload", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error("when tried to parse multipart form: ", err.Error())
+			return
+		}
+		if r.MultipartForm.File["video"] == nil {
+			t.Error("video field is missing from the upload")
+		}
+		if r.FormValue("disable_audio") != "1" {
+			t.Error("disable_audio should've been sent")
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"id":"vid","deletehash":"del","type":"video/mp4",` +
+			`"processing":{"status":"pending"}},"success":true,"status":200}`))
+	})
+	mux.HandleFunc("/3/image/vid", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			_, _ = w.Write([]byte(`{"data":{"id":"vid","processing":{"status":"pending"}},"success":true,"status":200}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"vid","mp4":"https://i.imgur.com/vid.mp4",` +
+			`"gifv":"https://i.imgur.com/vid.gifv","processing":{"status":"completed"}},"success":true,"status":200}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := wotoImgur.NewImgurClient("client-id", &wotoImgur.ClientConfig{
+		HTTPClient: newTestHTTPClient(t, server),
+	})
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	if _, err = client.UploadVideo(bytes.NewReader([]byte("video")), &wotoImgur.UploadOptions{Name: "clip.avi"}); err == nil {
+		t.Error("expected an error for an unsupported video format")
+	}
+
+	info, err := client.UploadVideo(bytes.NewReader([]byte("video")), &wotoImgur.UploadOptions{
+		Name:         "clip.mp4",
+		DisableAudio: true,
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Error("when tried to upload video: ", err.Error())
+		return
+	}
+
+	if info.Mp4 == "" || info.Gifv == "" || info.DeleteHash != "del" {
+		t.Error("unexpected video info: ", info)
+	}
+
+	_, err = client.WaitForProcessing("vid", 10*time.Millisecond, 5*time.Millisecond)
+	if err != nil {
+		t.Error("already processed video should be returned right away: ", err.Error())
+	}
+}
+
+func TestWaitForProcessingTimeout(t *testing.T) {
+	server := wotoImgurtest.NewServer()
+	defer server.Close()
+
+	client, err := server.NewImgurClient("client-id", nil)
+	if err != nil {
+		t.Error("when tried to get new client: ", err.Error())
+		return
+	}
+
+	video := server.AddImage(wotoImgur.ImageInfo{
+		MimeType:   "video/mp4",
+		Processing: &wotoImgur.ProcessingInfo{Status: wotoImgur.ProcessingStatusPending},
+	}, nil)
+
+	_, err = client.WaitForProcessing(video.ID, time.Millisecond, 5*time.Millisecond)
+	var imgurErr *wotoImgur.ImgurError
+	if !errors.As(err, &imgurErr) || imgurErr.IsCanceled() || errors.Is(err, wotoImgur.ErrCanceled) ||
+		errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, wotoImgur.ErrServerError) {
+		t.Error("processing timeout shouldn't be reported as a cancellation: ", err)
+	}
+}
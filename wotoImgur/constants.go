@@ -1,6 +1,188 @@
 package wotoImgur
 
+import "time"
+
 const (
 	apiEndpoint         = "https://api.imgur.com/3/"
 	apiEndpointRapidAPI = "https://imgur-apiv3.p.rapidapi.com/3/"
+	rapidAPIHost        = "imgur-apiv3.p.rapidapi.com"
+
+	oauthAuthorizeEndpoint = "https://api.imgur.com/oauth2/authorize"
+	oauthTokenEndpoint     = "https://api.imgur.com/oauth2/token"
+)
+
+// redactedValue replaces secrets in logs.
+const redactedValue = "REDACTED"
+
+// DefaultProgressInterval is the default minimum interval between
+// two upload progress events.
+const DefaultProgressInterval = 500 * time.Millisecond
+
+// default parameters of waiting for an uploaded video to be processed.
+const (
+	DefaultVideoPollInterval      = 2 * time.Second
+	DefaultVideoProcessingTimeout = 2 * time.Minute
+)
+
+// default parameters of a RetryPolicy, used when its fields are left zero.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 30 * time.Second
+)
+
+// processing statuses of uploaded videos.
+const (
+	ProcessingStatusPending   = "pending"
+	ProcessingStatusCompleted = "completed"
+	ProcessingStatusFailed    = "failed"
+)
+
+// privacy levels of an album.
+const (
+	AlbumPrivacyPublic AlbumPrivacy = "public"
+	AlbumPrivacyHidden AlbumPrivacy = "hidden"
+	AlbumPrivacySecret AlbumPrivacy = "secret"
+)
+
+// layouts of an album.
+const (
+	AlbumLayoutBlog       AlbumLayout = "blog"
+	AlbumLayoutGrid       AlbumLayout = "grid"
+	AlbumLayoutHorizontal AlbumLayout = "horizontal"
+	AlbumLayoutVertical   AlbumLayout = "vertical"
+)
+
+// sections of the gallery.
+const (
+	GallerySectionHot  GallerySection = "hot"
+	GallerySectionTop  GallerySection = "top"
+	GallerySectionUser GallerySection = "user"
+)
+
+// sort orders of gallery items.
+const (
+	GallerySortViral GallerySort = "viral"
+	GallerySortTop   GallerySort = "top"
+	GallerySortTime  GallerySort = "time"
+
+	// GallerySortRising is only available with GallerySectionUser.
+	GallerySortRising GallerySort = "rising"
+)
+
+// date ranges of gallery items, only used with GallerySectionTop
+// or GallerySortTop.
+const (
+	GalleryWindowDay   GalleryWindow = "day"
+	GalleryWindowWeek  GalleryWindow = "week"
+	GalleryWindowMonth GalleryWindow = "month"
+	GalleryWindowYear  GalleryWindow = "year"
+	GalleryWindowAll   GalleryWindow = "all"
+)
+
+// file types of gallery search results.
+const (
+	SearchTypeJPG    SearchType = "jpg"
+	SearchTypePNG    SearchType = "png"
+	SearchTypeGIF    SearchType = "gif"
+	SearchTypeAniGIF SearchType = "anigif"
+	SearchTypeAlbum  SearchType = "album"
+)
+
+// image sizes of gallery search results.
+const (
+	// SearchSizeSmall is up to 500 pixels square.
+	SearchSizeSmall SearchSize = "small"
+
+	// SearchSizeMedium is 500 to 2,000 pixels square.
+	SearchSizeMedium SearchSize = "med"
+
+	// SearchSizeBig is 2,000 to 5,000 pixels square.
+	SearchSizeBig SearchSize = "big"
+
+	// SearchSizeLarge is 5,000 to 10,000 pixels square.
+	SearchSizeLarge SearchSize = "lrg"
+
+	// SearchSizeHuge is more than 10,000 pixels square.
+	SearchSizeHuge SearchSize = "huge"
+)
+
+// sort orders of comments.
+const (
+	CommentSortBest CommentSort = "best"
+	CommentSortTop  CommentSort = "top"
+	CommentSortNew  CommentSort = "new"
+)
+
+// votes on comments and gallery items.
+const (
+	VoteUp   Vote = "up"
+	VoteDown Vote = "down"
+
+	// VoteVeto removes a previous vote.
+	VoteVeto Vote = "veto"
+)
+
+// kinds of an ImgurError.
+const (
+	// ErrorKindUnknown is the kind of errors not falling into any other kind.
+	ErrorKindUnknown ErrorKind = iota
+
+	// ErrorKindNotFound means the requested resource doesn't exist.
+	ErrorKindNotFound
+
+	// ErrorKindUnauthorized means the request lacks valid authentication,
+	// or an operation needs an authenticated client.
+	ErrorKindUnauthorized
+
+	// ErrorKindForbidden means the authenticated user isn't allowed to
+	// perform the request.
+	ErrorKindForbidden
+
+	// ErrorKindRateLimited means the credits of the user or the application
+	// have been used up.
+	ErrorKindRateLimited
+
+	// ErrorKindServerError means imgur failed to handle the request.
+	ErrorKindServerError
+
+	// ErrorKindDecode means the response of imgur couldn't be decoded.
+	ErrorKindDecode
+
+	// ErrorKindTransport means the request couldn't be sent or its response
+	// couldn't be received.
+	ErrorKindTransport
+
+	// ErrorKindValidation means invalid parameters have been passed,
+	// so no request has been sent.
+	ErrorKindValidation
+
+	// ErrorKindCanceled means the context of the request has been canceled
+	// or its deadline has been exceeded.
+	ErrorKindCanceled
+
+	// ErrorKindBadRequest means imgur rejected the request with a 4xx status
+	// not covered by any other kind, e.g. 400 or 413.
+	ErrorKindBadRequest
+)
+
+// AccountMe is the username referring to the authenticated account.
+const AccountMe = "me"
+
+// tokenExpiryDelta is how long before its actual expiry an access token
+// is considered expired, so it doesn't run out in the middle of a request.
+const tokenExpiryDelta = time.Minute
+
+// response types accepted by imgur's oauth2 authorize endpoint.
+const (
+	ResponseTypeCode  OAuthResponseType = "code"
+	ResponseTypeToken OAuthResponseType = "token"
+	ResponseTypePin   OAuthResponseType = "pin"
+)
+
+// grant types accepted by imgur's oauth2 token endpoint.
+const (
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypePin               = "pin"
+	grantTypeRefreshToken      = "refresh_token"
 )
@@ -1,11 +1,21 @@
 package wotoImgur
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,15 +29,35 @@ func NewImgurClient(token string, config *ClientConfig) (*ImgurClient, error) {
 	}
 
 	if token == "" {
-		return nil, errors.New("invalid imgur client-id provided")
+		return nil, getValidationErr("invalid imgur client-id provided")
 	}
 
 	client := &ImgurClient{
-		HTTPClient:    config.HTTPClient,
-		ImgurClientID: token,
-		RapidAPIKey:   config.RapidAPIKey,
+		HTTPClient:        config.HTTPClient,
+		ImgurClientID:     token,
+		ClientSecret:      config.ClientSecret,
+		RapidAPIKey:       config.RapidAPIKey,
+		tokenSource:       config.TokenSource,
+		onTokenRotate:     config.OnTokenRotate,
+		oauthAuthorizeURL: config.AuthorizeURL,
+		oauthTokenURL:     config.TokenURL,
+		baseURL:           normalizeBaseURL(config.BaseURL),
+		uploadURL:         normalizeBaseURL(config.UploadURL),
+		retryPolicy:       config.RetryPolicy,
+		rateLimitPolicy:   config.RateLimitPolicy,
+		logger:            config.Logger,
 	}
 
+	if client.tokenSource == nil {
+		client.tokenSource = NewMemoryTokenSource(config.Token)
+	} else if config.Token != nil {
+		if err := client.tokenSource.SetToken(config.Token); err != nil {
+			return nil, wrapErr(-1, "Could not store the provided token", err)
+		}
+	}
+
+	client.handler = chainMiddlewares(client.send, config.Middlewares)
+
 	return client, nil
 }
 
@@ -37,6 +67,55 @@ func GetDefaultConfig() *ClientConfig {
 	}
 }
 
+// GetDefaultRetryPolicy returns a retry policy retrying failed requests
+// up to DefaultRetryMaxAttempts times, with a jitter of 20%.
+func GetDefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      0.2,
+	}
+}
+
+// normalizeBaseURL makes sure the given root of the api ends with a slash,
+// so endpoints can be appended to it.
+func normalizeBaseURL(baseURL string) string {
+	if baseURL == "" || strings.HasSuffix(baseURL, "/") {
+		return baseURL
+	}
+	return baseURL + "/"
+}
+
+// isIdempotentMethod returns true if sending a request with the method
+// more than once has the same effect as sending it once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is
+// either a number of seconds or a HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+
+	return 0, false
+}
+
 func createUploadForm(image []byte, album, dType, title, description string) url.Values {
 	form := url.Values{}
 
@@ -56,6 +135,375 @@ func createUploadForm(image []byte, album, dType, title, description string) url
 	return form
 }
 
+// NewSearchQuery returns a SearchQuery for the given query string, which
+// supports imgur's boolean operators (AND, OR, NOT) and field prefixes
+// (tag:, user:, title:, ext:, subreddit:, album:, meme:).
+// query may be empty if advanced parameters are set instead.
+func NewSearchQuery(query string) *SearchQuery {
+	return &SearchQuery{
+		query: query,
+	}
+}
+
+// FlattenComments returns the comments along with all of their replies
+// as a flat list, in depth-first order.
+func FlattenComments(comments []Comment) []Comment {
+	var flat []Comment
+	for i := range comments {
+		flat = append(flat, comments[i].Flatten()...)
+	}
+
+	return flat
+}
+
+// NewPager returns a Pager iterating over the pages returned by fetch.
+// It stops on the first empty page, or once maxItems items have been
+// returned; maxItems <= 0 means no limit.
+func NewPager[T any](fetch PageFetcher[T], maxItems int) *Pager[T] {
+	return &Pager[T]{
+		fetch:    fetch,
+		maxItems: maxItems,
+	}
+}
+
+// NewMemoryTokenSource returns a TokenSource holding the given token in memory.
+// token may be nil.
+func NewMemoryTokenSource(token *OAuthToken) *MemoryTokenSource {
+	return &MemoryTokenSource{
+		mut:   &sync.Mutex{},
+		token: token,
+	}
+}
+
+// NewFileTokenSource returns a TokenSource persisting the token to the
+// given json file. If the file already exists, the token is loaded from it.
+func NewFileTokenSource(path string) (*FileTokenSource, error) {
+	source := &FileTokenSource{
+		mut:  &sync.Mutex{},
+		path: path,
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return source, nil
+		}
+		return nil, newErr(ErrorKindValidation, "Could not read token file "+path, err)
+	}
+
+	if len(b) == 0 {
+		return source, nil
+	}
+
+	token := new(OAuthToken)
+	if err = json.Unmarshal(b, token); err != nil {
+		return nil, getDecodeErr("Could not decode token file "+path, err)
+	}
+	source.token = token
+
+	return source, nil
+}
+
+func createAlbumForm(opts *AlbumOptions) url.Values {
+	form := url.Values{}
+
+	if opts.Title != "" {
+		form.Add("title", opts.Title)
+	}
+	if opts.Description != "" {
+		form.Add("description", opts.Description)
+	}
+	if opts.Privacy != "" {
+		form.Add("privacy", string(opts.Privacy))
+	}
+	if opts.Layout != "" {
+		form.Add("layout", string(opts.Layout))
+	}
+	if opts.Cover != "" {
+		form.Add("cover", opts.Cover)
+	}
+	for _, id := range opts.IDs {
+		form.Add("ids[]", id)
+	}
+	for _, deleteHash := range opts.DeleteHashes {
+		form.Add("deletehashes[]", deleteHash)
+	}
+
+	return form
+}
+
+func createAccountSettingsForm(opts *AccountSettingsOptions) url.Values {
+	form := url.Values{}
+
+	if opts.Bio != "" {
+		form.Add("bio", opts.Bio)
+	}
+	if opts.AlbumPrivacy != "" {
+		form.Add("album_privacy", string(opts.AlbumPrivacy))
+	}
+	if opts.Username != "" {
+		form.Add("username", opts.Username)
+	}
+
+	flags := []struct {
+		name  string
+		value *bool
+	}{
+		{"public_images", opts.PublicImages},
+		{"messaging_enabled", opts.MessagingEnabled},
+		{"accepted_gallery_terms", opts.AcceptedGalleryTerms},
+		{"show_mature", opts.ShowMature},
+		{"newsletter_subscribed", opts.NewsletterSubscribed},
+	}
+	for _, flag := range flags {
+		if flag.value != nil {
+			form.Add(flag.name, strconv.FormatBool(*flag.value))
+		}
+	}
+
+	return form
+}
+
+func createIDsForm(ids []string) url.Values {
+	form := url.Values{}
+
+	for _, id := range ids {
+		form.Add("ids[]", id)
+	}
+
+	return form
+}
+
+func isValidVote(vote Vote) bool {
+	return vote == VoteUp || vote == VoteDown || vote == VoteVeto
+}
+
+func createGallerySubmitForm(title, topic string, terms, mature bool, tags []string) url.Values {
+	form := url.Values{}
+
+	form.Add("title", title)
+	if topic != "" {
+		form.Add("topic", topic)
+	}
+	if terms {
+		form.Add("terms", "1")
+	}
+	if mature {
+		form.Add("mature", "1")
+	}
+	if len(tags) != 0 {
+		form.Add("tags", strings.Join(tags, ","))
+	}
+
+	return form
+}
+
+func createGalleryPath(section GallerySection, sort GallerySort, window GalleryWindow, page int) string {
+	if section == "" {
+		section = GallerySectionHot
+	}
+	if sort == "" {
+		sort = GallerySortViral
+	}
+	if window == "" {
+		window = GalleryWindowDay
+	}
+
+	return "gallery/" + string(section) + "/" + string(sort) + "/" + string(window) + "/" + strconv.Itoa(page)
+}
+
+// createGalleryListPath returns the path of a gallery list under the
+// given prefix, such as a topic or a tag.
+func createGalleryListPath(prefix string, sort GallerySort, window GalleryWindow, page int) string {
+	if sort == "" {
+		sort = GallerySortViral
+	}
+	if window == "" {
+		window = GalleryWindowWeek
+	}
+
+	return prefix + "/" + string(sort) + "/" + string(window) + "/" + strconv.Itoa(page)
+}
+
+func createGalleryQuery(showViral, mature, albumPreviews bool) string {
+	values := url.Values{}
+
+	values.Add("showViral", strconv.FormatBool(showViral))
+	values.Add("mature", strconv.FormatBool(mature))
+	values.Add("album_previews", strconv.FormatBool(albumPreviews))
+
+	return values.Encode()
+}
+
+// ParseTokenFromRedirectURL extracts the oauth2 token that imgur appends
+// to the fragment of the redirect url when using ResponseTypeToken.
+func ParseTokenFromRedirectURL(redirectURL string) (*OAuthToken, error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return nil, newErr(ErrorKindValidation, "Could not parse redirect URL "+redirectURL, err)
+	}
+
+	values, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return nil, newErr(ErrorKindValidation, "Could not parse fragment of redirect URL "+redirectURL, err)
+	}
+
+	if errStr := values.Get("error"); errStr != "" {
+		return nil, getAuthErr("Authorization failed - " + errStr)
+	}
+
+	token := &OAuthToken{
+		AccessToken:     values.Get("access_token"),
+		RefreshToken:    values.Get("refresh_token"),
+		TokenType:       values.Get("token_type"),
+		AccountUsername: values.Get("account_username"),
+	}
+	if token.AccessToken == "" {
+		return nil, getValidationErr("No access token found in redirect URL " + redirectURL)
+	}
+
+	token.ExpiresIn, _ = strconv.ParseInt(values.Get("expires_in"), 10, 64)
+	token.AccountID, _ = strconv.ParseInt(values.Get("account_id"), 10, 64)
+	token.setExpiry()
+
+	return token, nil
+}
+
+func createTokenForm(clientID, clientSecret, grantType string) url.Values {
+	form := url.Values{}
+
+	form.Add("client_id", clientID)
+	form.Add("client_secret", clientSecret)
+	form.Add("grant_type", grantType)
+
+	return form
+}
+
+// createMultipartBody returns a reader streaming the multipart/form-data
+// body of an upload, along with its content type. The content of r is copied
+// into the body by a separate goroutine as the body is being read; the
+// returned channel is closed once that goroutine is done with r, which
+// happens early if the body is closed before being read entirely.
+func createMultipartBody(field string, r io.Reader, opts *UploadOptions) (io.ReadCloser, string, <-chan struct{}) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		pw.CloseWithError(writeMultipartBody(mw, field, r, opts))
+	}()
+
+	return pr, mw.FormDataContentType(), done
+}
+
+func writeMultipartBody(mw *multipart.Writer, field string, r io.Reader, opts *UploadOptions) error {
+	fields := [][2]string{
+		{"type", "file"},
+		{"album", opts.Album},
+		{"title", opts.Title},
+		{"description", opts.Description},
+		{"name", opts.Name},
+	}
+	if opts.DisableAudio {
+		fields = append(fields, [2]string{"disable_audio", "1"})
+	}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		if err := mw.WriteField(f[0], f[1]); err != nil {
+			return err
+		}
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = field
+	}
+
+	contentType := getContentType(name)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		multipartEscaper.Replace(field), multipartEscaper.Replace(name)))
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(part, r); err != nil {
+		return err
+	}
+
+	return mw.Close()
+}
+
+// getContentType guesses the content type of a file from its name.
+func getContentType(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if contentType, ok := videoContentTypes[ext]; ok {
+		return contentType
+	}
+
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+
+	return "application/octet-stream"
+}
+
+// isVideoFile returns true if the name has the extension of a video
+// format accepted by imgur.
+func isVideoFile(name string) bool {
+	_, ok := videoContentTypes[strings.ToLower(filepath.Ext(name))]
+	return ok
+}
+
+// newProgressReader wraps r so reading from it reports progress
+// to opts.OnProgress. If no callback is set, r is returned as is.
+func newProgressReader(r io.Reader, total int64, opts *UploadOptions) io.Reader {
+	if opts.OnProgress == nil {
+		return r
+	}
+
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+
+	return &progressReader{
+		reader:     r,
+		onProgress: opts.OnProgress,
+		interval:   interval,
+		total:      total,
+		started:    time.Now(),
+	}
+}
+
+// getReaderSize returns the number of bytes left in r,
+// or -1 if it can't be known without reading it.
+func getReaderSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+
+	return -1
+}
+
 func extractRateLimits(h http.Header) (*RateLimit, error) {
 	rl := new(RateLimit)
 	var err error
@@ -87,6 +535,24 @@ func extractRateLimits(h http.Header) (*RateLimit, error) {
 		rl.ClientRemaining, err = strconv.ParseInt(clientRemainingStr, 10, 32)
 	}
 
+	postLimitStr := h.Get("X-Post-Rate-Limit-Limit")
+	if postLimitStr != "" {
+		rl.PostLimit, err = strconv.ParseInt(postLimitStr, 10, 32)
+	}
+
+	postRemainingStr := h.Get("X-Post-Rate-Limit-Remaining")
+	if postRemainingStr != "" {
+		rl.PostRemaining, err = strconv.ParseInt(postRemainingStr, 10, 32)
+	}
+
+	// unlike UserReset, the post reset is the number of seconds until the reset.
+	postResetStr := h.Get("X-Post-Rate-Limit-Reset")
+	if postResetStr != "" {
+		var postReset int64
+		postReset, err = strconv.ParseInt(postResetStr, 10, 64)
+		rl.PostReset = time.Now().Add(time.Duration(postReset) * time.Second)
+	}
+
 	return rl, err
 }
 
@@ -94,6 +560,7 @@ func getErr(status int, value string) *ImgurError {
 	return &ImgurError{
 		Status:  status,
 		Message: value,
+		Kind:    getErrorKind(status),
 	}
 }
 
@@ -101,5 +568,132 @@ func getErrF(status int, format string, args ...any) *ImgurError {
 	return &ImgurError{
 		Err:    fmt.Errorf(format, args...),
 		Status: status,
+		Kind:   getErrorKind(status),
+	}
+}
+
+func newErr(kind ErrorKind, message string, err error) *ImgurError {
+	return &ImgurError{
+		Err:     err,
+		Status:  -1,
+		Message: message,
+		Kind:    kind,
+	}
+}
+
+func getValidationErr(message string) *ImgurError {
+	return newErr(ErrorKindValidation, message, nil)
+}
+
+func getAuthErr(message string) *ImgurError {
+	return newErr(ErrorKindUnauthorized, message, nil)
+}
+
+func getDecodeErr(message string, err error) *ImgurError {
+	return newErr(ErrorKindDecode, message, err)
+}
+
+// wrapErr wraps err with the message. If err is an ImgurError, its kind,
+// payload and (unless status is given) its status are kept.
+func wrapErr(status int, message string, err error) *ImgurError {
+	wrapped := &ImgurError{
+		Err:     err,
+		Status:  status,
+		Message: message,
+		Kind:    getErrorKind(status),
+	}
+
+	var inner *ImgurError
+	if errors.As(err, &inner) {
+		if status == -1 {
+			wrapped.Status = inner.Status
+		}
+		wrapped.Kind = inner.Kind
+		wrapped.Payload = inner.Payload
+	}
+
+	return wrapped
+}
+
+// chainMiddlewares wraps h with the middlewares, the first one being the outermost.
+func chainMiddlewares(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// closeBody closes the body of a request which is never sent.
+func closeBody(body io.Reader) {
+	if closer, ok := body.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// getRequestErr returns the error for a failed http round trip, keeping
+// the context's error when the request has been canceled or timed out,
+// so it can be told apart from transport failures.
+func getRequestErr(ctx context.Context, message string, err error) *ImgurError {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return newErr(ErrorKindCanceled, message+" - request canceled", ctxErr)
 	}
+	return newErr(ErrorKindTransport, message, err)
+}
+
+// getHTTPErr returns the error for a response with a non-2xx status,
+// keeping the error data imgur has sent along with it.
+func getHTTPErr(res *http.Response, body []byte, message string) *ImgurError {
+	imgurErr := getErr(res.StatusCode, message+" - "+res.Status)
+	imgurErr.Payload = parseErrorPayload(body)
+	if imgurErr.Payload != nil && imgurErr.Payload.Error != "" {
+		imgurErr.Message += " - " + imgurErr.Payload.Error
+	}
+
+	return imgurErr
+}
+
+// parseErrorPayload extracts the error data from the body of a failed
+// request, or returns nil if there is none.
+func parseErrorPayload(body []byte) *ErrorPayload {
+	var wrapper errorDataWrapper
+	if json.Unmarshal(body, &wrapper) != nil || wrapper.Data == nil {
+		return nil
+	}
+
+	payload := &ErrorPayload{
+		Request: wrapper.Data.Request,
+		Method:  wrapper.Data.Method,
+	}
+
+	// error is usually a string, but some endpoints send an object instead.
+	if json.Unmarshal(wrapper.Data.Error, &payload.Error) != nil {
+		var errObject struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(wrapper.Data.Error, &errObject) == nil {
+			payload.Error = errObject.Message
+		}
+	}
+
+	return payload
+}
+
+// getErrorKind returns the kind of error a HTTP status stands for.
+func getErrorKind(status int) ErrorKind {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrorKindNotFound
+	case status == http.StatusUnauthorized:
+		return ErrorKindUnauthorized
+	case status == http.StatusForbidden:
+		return ErrorKindForbidden
+	case status == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case status >= 500:
+		return ErrorKindServerError
+	case status >= 400:
+		return ErrorKindBadRequest
+	}
+
+	return ErrorKindUnknown
 }
@@ -2,13 +2,20 @@ package wotoImgur
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AnimeKaizoku/ssg/ssg"
 )
@@ -16,399 +23,3028 @@ import (
 // GetAlbumInfo queries imgur for information on a album
 // returns album info, status code of the request, error
 func (c *ImgurClient) GetAlbumInfo(id string) (*AlbumInfo, error) {
-	body, rl, err := c.getURL("album/" + id)
+	return c.GetAlbumInfoCtx(context.Background(), id)
+}
+
+// GetAlbumInfoCtx is like GetAlbumInfo, but uses the given context for its requests.
+func (c *ImgurClient) GetAlbumInfoCtx(ctx context.Context, id string) (*AlbumInfo, error) {
+	body, rl, err := c.getURL(ctx, "album/"+id)
 	if err != nil {
-		return nil, getErr(-1, "Problem getting URL for album info ID "+id+" - "+err.Error())
+		return nil, wrapErr(-1, "Problem getting URL for album info ID "+id, err)
 	}
-	//client.Log.Debugf("%v\n", body)
 
 	dec := json.NewDecoder(strings.NewReader(body))
 	var alb albumInfoDataWrapper
 	if err := dec.Decode(&alb); err != nil {
-		return nil, getErr(-1, "Problem decoding json for albumID "+id+" - "+err.Error())
+		return nil, getDecodeErr("Problem decoding json for albumID "+id, err)
 	}
+	c.setLastRateLimit(rl)
 
 	if !alb.Success {
 		return nil, getErr(alb.Status, "Request to imgur failed for albumID "+id+" - "+strconv.Itoa(alb.Status))
 	}
+	if alb.Ai == nil {
+		return nil, getDecodeErr("Imgur returned no data for albumID "+id, nil)
+	}
 
 	alb.Ai.Limit = rl
 	return alb.Ai, nil
 }
 
-// GetInfoFromURL tries to query imgur based on information identified in the URL.
-// returns image/album info, status code of the request, error
-func (c *ImgurClient) GetInfoFromURL(url string) (*GenericInfo, error) {
-	url = strings.TrimSpace(url)
-
-	// https://i.imgur.com/<id>.jpg -> image
-	if strings.Contains(url, "://i.imgur.com/") {
-		return c.directImageURL(url)
-	}
-
-	// https://imgur.com/a/<id> -> album
-	if strings.Contains(url, "://imgur.com/a/") || strings.Contains(url, "://m.imgur.com/a/") {
-		return c.albumURL(url)
-	}
+// GetAlbumImages queries imgur for all images of an album.
+// Unlike AlbumInfo.Images, the result is never truncated.
+func (c *ImgurClient) GetAlbumImages(albumID string) ([]ImageInfo, error) {
+	return c.GetAlbumImagesCtx(context.Background(), albumID)
+}
 
-	// https://imgur.com/gallery/<id> -> gallery album
-	if strings.Contains(url, "://imgur.com/gallery/") || strings.Contains(url, "://m.imgur.com/gallery/") {
-		return c.galleryURL(url)
+// GetAlbumImagesCtx is like GetAlbumImages, but uses the given context for its requests.
+func (c *ImgurClient) GetAlbumImagesCtx(ctx context.Context, albumID string) ([]ImageInfo, error) {
+	if albumID == "" {
+		return nil, getValidationErr("Invalid album id")
 	}
 
-	// https://imgur.com/<id> -> image
-	if strings.Contains(url, "://imgur.com/") || strings.Contains(url, "://m.imgur.com/") {
-		return c.imageURL(url)
-	}
+	images, _, err := c.getImageList(ctx, "album/"+albumID+"/images", "images of albumID "+albumID)
+	return images, err
+}
 
-	return nil, getErr(-1, "URL pattern matching for URL "+url+" failed.")
+// GetAlbumImage queries imgur for information on an image of an album.
+func (c *ImgurClient) GetAlbumImage(albumID, imageID string) (*ImageInfo, error) {
+	return c.GetAlbumImageCtx(context.Background(), albumID, imageID)
 }
 
-func (c *ImgurClient) directImageURL(url string) (*GenericInfo, error) {
-	var ret GenericInfo
-	start := strings.LastIndex(url, "/") + 1
-	end := strings.LastIndex(url, ".")
-	if start+1 >= end {
-		return nil, getErr(-1, "Could not find ID in URL "+url+". I was going down i.imgur.com path.")
-	}
-	id := url[start:end]
-	// client.Log.Debugf("Detected imgur image ID %v. Was going down the i.imgur.com/ path.", id)
-	gii, err := c.GetGalleryImageInfo(id)
-	if err == nil {
-		ret.GImage = gii
-	} else {
-		var ii *ImageInfo
-		ii, err = c.GetImageInfo(id)
-		ret.Image = ii
+// GetAlbumImageCtx is like GetAlbumImage, but uses the given context for its requests.
+func (c *ImgurClient) GetAlbumImageCtx(ctx context.Context, albumID, imageID string) (*ImageInfo, error) {
+	if albumID == "" || imageID == "" {
+		return nil, getValidationErr("Invalid album or image id")
 	}
-	return &ret, err
-}
 
-func (c *ImgurClient) albumURL(url string) (*GenericInfo, error) {
-	var ret GenericInfo
+	body, rl, err := c.getURL(ctx, "album/"+albumID+"/image/"+imageID)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for imageID "+imageID+" of albumID "+albumID, err)
+	}
 
-	start := strings.LastIndex(url, "/") + 1
-	end := strings.LastIndex(url, "?")
-	if end == -1 {
-		end = len(url)
+	dec := json.NewDecoder(strings.NewReader(body))
+	var img imageInfoDataWrapper
+	if err := dec.Decode(&img); err != nil {
+		return nil, getDecodeErr("Problem decoding json for imageID "+imageID+" of albumID "+albumID, err)
 	}
-	id := url[start:end]
-	if id == "" {
-		return nil, getErr(-1, "Could not find ID in URL "+url+". I was going down imgur.com/a/ path.")
+	c.setLastRateLimit(rl)
+
+	if !img.Success || img.Info == nil {
+		return nil, getErr(img.Status, "Request to imgur failed for imageID "+imageID+" of albumID "+albumID+" - "+strconv.Itoa(img.Status))
 	}
-	// client.Log.Debugf("Detected imgur album ID %v. Was going down the imgur.com/a/ path.", id)
-	ai, err := c.GetAlbumInfo(id)
-	ret.Album = ai
-	return &ret, err
+
+	img.Info.Limit = rl
+	return img.Info, nil
 }
 
-func (c *ImgurClient) galleryURL(url string) (*GenericInfo, error) {
-	var ret GenericInfo
+// getImageList queries imgur for a list of images,
+// attaching the rate limit to each of them.
+func (c *ImgurClient) getImageList(ctx context.Context, theUrl, what string) ([]ImageInfo, *RateLimit, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return nil, nil, wrapErr(-1, "Problem getting URL for "+what, err)
+	}
 
-	start := strings.LastIndex(url, "/") + 1
-	end := strings.LastIndex(url, "?")
-	if end == -1 {
-		end = len(url)
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list imageListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, nil, getDecodeErr("Problem decoding json for "+what, err)
 	}
-	id := url[start:end]
-	if id == "" {
-		return nil, getErr(-1, "Could not find ID in URL "+url+". I was going down imgur.com/gallery/ path.")
+	c.setLastRateLimit(rl)
+
+	if !list.Success {
+		return nil, nil, getErr(list.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(list.Status))
 	}
 
-	// client.Log.Debugf("Detected imgur gallery ID %v. Was going down the imgur.com/gallery/ path.", id)
-	ai, err := c.GetGalleryAlbumInfo(id)
-	if err == nil {
-		ret.GAlbum = ai
-		return &ret, err
+	for i := range list.Images {
+		list.Images[i].Limit = rl
 	}
-	// fallback to GetGalleryImageInfo
-	// client.Log.Debugf("Failed to retrieve imgur gallery album. Attempting to retrieve imgur gallery image. err: %v status: %d", err, status)
-	ii, err := c.GetGalleryImageInfo(id)
-	ret.GImage = ii
-	return &ret, err
+
+	return list.Images, rl, nil
 }
 
-func (c *ImgurClient) imageURL(url string) (*GenericInfo, error) {
-	var ret GenericInfo
+// CreateAlbum creates a new album. Anonymous albums can only be modified
+// using the deletehash of the returned album info, so keep it around.
+// opts may be nil.
+func (c *ImgurClient) CreateAlbum(opts *AlbumOptions) (*AlbumInfo, error) {
+	return c.CreateAlbumCtx(context.Background(), opts)
+}
 
-	start := strings.LastIndex(url, "/") + 1
-	end := strings.LastIndex(url, "?")
-	if end == -1 {
-		end = len(url)
-	}
-	id := url[start:end]
-	if id == "" {
-		return nil, getErr(-1, "Could not find ID in URL "+url+". I was going down imgur.com/ path.")
+// CreateAlbumCtx is like CreateAlbum, but uses the given context for its requests.
+func (c *ImgurClient) CreateAlbumCtx(ctx context.Context, opts *AlbumOptions) (*AlbumInfo, error) {
+	if opts == nil {
+		opts = &AlbumOptions{}
 	}
-	// client.Log.Debugf("Detected imgur image ID %v. Was going down the imgur.com/ path.", id)
-	ii, err := c.GetGalleryImageInfo(id)
-	if err == nil {
-		ret.GImage = ii
-		return &ret, nil
-	}
-
-	i, err := c.GetImageInfo(id)
-	ret.Image = i
-	return &ret, err
-}
 
-// GetGalleryAlbumInfo queries imgur for information on a gallery album
-// returns album info, status code of the request, error
-func (c *ImgurClient) GetGalleryAlbumInfo(id string) (*GalleryAlbumInfo, error) {
-	body, rl, err := c.getURL("gallery/album/" + id)
+	body, rl, err := c.postURL(ctx, "album", createAlbumForm(opts))
 	if err != nil {
-		return nil, getErr(-1, "Problem getting URL for gallery album info ID "+id+" - "+err.Error())
+		return nil, wrapErr(-1, "Problem creating album", err)
 	}
-	// client.Log.Debugf("%v\n", body)
 
 	dec := json.NewDecoder(strings.NewReader(body))
-	var alb galleryAlbumInfoDataWrapper
+	var alb albumInfoDataWrapper
 	if err := dec.Decode(&alb); err != nil {
-		return nil, getErr(-1, "Problem decoding json for gallery albumID "+id+" - "+err.Error())
+		return nil, getDecodeErr("Problem decoding json for created album", err)
 	}
-	alb.Ai.Limit = rl
+	c.setLastRateLimit(rl)
 
-	if !alb.Success {
-		return nil, getErr(alb.Status, "Request to imgur failed for gallery albumID "+id+" - "+strconv.Itoa(alb.Status))
+	if !alb.Success || alb.Ai == nil {
+		return nil, getErr(alb.Status, "Request to imgur failed for creating album - "+strconv.Itoa(alb.Status))
 	}
+
+	alb.Ai.Limit = rl
 	return alb.Ai, nil
 }
 
-// GetGalleryImageInfo queries imgur for information on a image
-// returns image info, status code of the request, error
-func (c *ImgurClient) GetGalleryImageInfo(id string) (*GalleryImageInfo, error) {
-	body, rl, err := c.getURL("gallery/image/" + id)
+// UpdateAlbum updates the album. Only the non-empty values of opts are changed,
+// except for IDs which, if set, replace all images of the album.
+// For anonymous albums, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) UpdateAlbum(idOrDeleteHash string, opts *AlbumOptions) error {
+	return c.UpdateAlbumCtx(context.Background(), idOrDeleteHash, opts)
+}
+
+// UpdateAlbumCtx is like UpdateAlbum, but uses the given context for its requests.
+func (c *ImgurClient) UpdateAlbumCtx(ctx context.Context, idOrDeleteHash string, opts *AlbumOptions) error {
+	if idOrDeleteHash == "" {
+		return getValidationErr("Invalid album id or deletehash")
+	}
+	if opts == nil {
+		opts = &AlbumOptions{}
+	}
+
+	body, rl, err := c.putURL(ctx, "album/"+idOrDeleteHash, createAlbumForm(opts))
 	if err != nil {
-		return nil, getErr(-1, "Problem getting URL for gallery image info ID "+id+" - "+err.Error())
+		return wrapErr(-1, "Problem updating album "+idOrDeleteHash, err)
 	}
-	// client.Log.Debugf("%v\n", body)
 
-	dec := json.NewDecoder(strings.NewReader(body))
-	var img galleryImageInfoDataWrapper
-	if err := dec.Decode(&img); err != nil {
-		return nil, getErr(-1, "Problem decoding json for gallery imageID "+id+" - "+err.Error())
+	return c.decodeBasicResponse(body, rl, "updating album "+idOrDeleteHash)
+}
+
+// DeleteAlbum deletes the album, without deleting the images in it.
+// For anonymous albums, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) DeleteAlbum(idOrDeleteHash string) error {
+	return c.DeleteAlbumCtx(context.Background(), idOrDeleteHash)
+}
+
+// DeleteAlbumCtx is like DeleteAlbum, but uses the given context for its requests.
+func (c *ImgurClient) DeleteAlbumCtx(ctx context.Context, idOrDeleteHash string) error {
+	if idOrDeleteHash == "" {
+		return getValidationErr("Invalid album id or deletehash")
 	}
-	img.Ii.Limit = rl
 
-	if !img.Success {
-		return nil, getErr(img.Status, "Request to imgur failed for gallery imageID "+id+" - "+strconv.Itoa(img.Status))
+	body, rl, err := c.deleteURL(ctx, "album/"+idOrDeleteHash)
+	if err != nil {
+		return wrapErr(-1, "Problem deleting album "+idOrDeleteHash, err)
 	}
-	return img.Ii, nil
+
+	return c.decodeBasicResponse(body, rl, "deleting album "+idOrDeleteHash)
 }
 
-func (c *ImgurClient) createAPIURL(u string) string {
-	if c.RapidAPIKey == "" {
-		return apiEndpoint + u
-	}
-	return apiEndpointRapidAPI + u
+// AddImagesToAlbum adds the images to the album, keeping the ones already in it.
+// For anonymous albums, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) AddImagesToAlbum(idOrDeleteHash string, ids []string) error {
+	return c.AddImagesToAlbumCtx(context.Background(), idOrDeleteHash, ids)
 }
 
-func (c *ImgurClient) GetLastRateLimit() (*RateLimit, error) {
-	return c.lastRateLimit, c.lastRateLimitErr
+// AddImagesToAlbumCtx is like AddImagesToAlbum, but uses the given context for its requests.
+func (c *ImgurClient) AddImagesToAlbumCtx(ctx context.Context, idOrDeleteHash string, ids []string) error {
+	return c.changeAlbumImages(ctx, "album/"+idOrDeleteHash+"/add", idOrDeleteHash, ids,
+		"adding images to album "+idOrDeleteHash)
 }
 
-// getURL returns
-// - body as string
-// - RateLimit with current limits
-// - error in case something broke
-func (c *ImgurClient) getURL(theUrl string) (string, *RateLimit, error) {
-	theUrl = c.createAPIURL(theUrl)
-	// client.Log.Infof("Requesting URL %v\n", URL)
-	req, err := http.NewRequest("GET", theUrl, nil)
-	if err != nil {
-		return "", nil, errors.New("Could not create request for " + theUrl + " - " + err.Error())
-	}
+// RemoveImagesFromAlbum removes the images from the album, without deleting them.
+// For anonymous albums, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) RemoveImagesFromAlbum(idOrDeleteHash string, ids []string) error {
+	return c.RemoveImagesFromAlbumCtx(context.Background(), idOrDeleteHash, ids)
+}
 
-	req.Header.Add("Authorization", "Client-ID "+c.ImgurClientID)
-	if c.RapidAPIKey != "" {
-		req.Header.Add("x-rapidapi-host", "imgur-apiv3.p.rapidapi.com")
-		req.Header.Add("x-rapidapi-key", c.RapidAPIKey)
-	}
+// RemoveImagesFromAlbumCtx is like RemoveImagesFromAlbum, but uses the given context for its requests.
+func (c *ImgurClient) RemoveImagesFromAlbumCtx(ctx context.Context, idOrDeleteHash string, ids []string) error {
+	return c.changeAlbumImages(ctx, "album/"+idOrDeleteHash+"/remove_images", idOrDeleteHash, ids,
+		"removing images from album "+idOrDeleteHash)
+}
 
-	// Make a request to the sourceURL
-	res, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return "", nil, errors.New("Could not get " + theUrl + " - " + err.Error())
-	}
-	defer res.Body.Close()
+// SetAlbumImages replaces all images of the album with the given ones.
+// For anonymous albums, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) SetAlbumImages(idOrDeleteHash string, ids []string) error {
+	return c.SetAlbumImagesCtx(context.Background(), idOrDeleteHash, ids)
+}
 
-	if !(res.StatusCode >= 200 && res.StatusCode <= 300) {
-		return "", nil, errors.New("HTTP status indicates an error for " + theUrl + " - " + res.Status)
-	}
+// SetAlbumImagesCtx is like SetAlbumImages, but uses the given context for its requests.
+func (c *ImgurClient) SetAlbumImagesCtx(ctx context.Context, idOrDeleteHash string, ids []string) error {
+	return c.changeAlbumImages(ctx, "album/"+idOrDeleteHash, idOrDeleteHash, ids,
+		"setting images of album "+idOrDeleteHash)
+}
 
-	// Read the whole body
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return "", nil, errors.New("Problem reading the body for " + theUrl + " - " + err.Error())
+func (c *ImgurClient) changeAlbumImages(ctx context.Context, theUrl, idOrDeleteHash string, ids []string, what string) error {
+	if idOrDeleteHash == "" {
+		return getValidationErr("Invalid album id or deletehash")
+	}
+	if len(ids) == 0 {
+		return getValidationErr("No image ids passed for " + what)
 	}
 
-	// Get RateLimit headers
-	rl, err := extractRateLimits(res.Header)
+	body, rl, err := c.postURL(ctx, theUrl, createIDsForm(ids))
 	if err != nil {
-		c.lastRateLimitErr = err
+		return wrapErr(-1, "Problem "+what, err)
 	}
 
-	return string(body[:]), rl, nil
+	return c.decodeBasicResponse(body, rl, what)
 }
 
-// GetImageInfo queries imgur for information on a image
-// returns image info, status code of the request, error
-func (c *ImgurClient) GetImageInfo(id string) (*ImageInfo, error) {
-	body, rl, err := c.getURL("image/" + id)
+// GetAccount queries imgur for the base information of an account.
+// Pass AccountMe or an empty username for the authenticated account.
+func (c *ImgurClient) GetAccount(username string) (*Account, error) {
+	return c.GetAccountCtx(context.Background(), username)
+}
+
+// GetAccountCtx is like GetAccount, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountCtx(ctx context.Context, username string) (*Account, error) {
+	username, err := c.resolveUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	body, rl, err := c.getURL(ctx, "account/"+username)
 	if err != nil {
-		return nil, getErr(-1, "Problem getting URL for image info ID "+id+" - "+err.Error())
+		return nil, wrapErr(-1, "Problem getting URL for account "+username, err)
 	}
 
 	dec := json.NewDecoder(strings.NewReader(body))
-	var img imageInfoDataWrapper
-	if err := dec.Decode(&img); err != nil {
-		return nil, getErr(-1, "Problem decoding json for imageID "+id+" - "+err.Error())
+	var acc accountDataWrapper
+	if err := dec.Decode(&acc); err != nil {
+		return nil, getDecodeErr("Problem decoding json for account "+username, err)
 	}
-	img.Info.Limit = rl
-	c.lastRateLimit = rl
-	c.lastRateLimitErr = nil
+	c.setLastRateLimit(rl)
 
-	if !img.Success {
-		return nil, getErr(img.Status, "Request to imgur failed for imageID "+id+" - "+strconv.Itoa(img.Status))
+	if !acc.Success || acc.Account == nil {
+		return nil, getErr(acc.Status, "Request to imgur failed for account "+username+" - "+strconv.Itoa(acc.Status))
 	}
-	return img.Info, nil
+
+	acc.Account.Limit = rl
+	return acc.Account, nil
 }
 
-// GetRateLimit returns the current rate limit without doing anything else
-func (c *ImgurClient) GetRateLimit() (*RateLimit, error) {
-	// We are requesting any URL and parse the returned HTTP headers
-	body, rl, err := c.getURL("account/kaffeeshare")
+// GetAccountSettings queries imgur for the settings of the authenticated account.
+func (c *ImgurClient) GetAccountSettings() (*AccountSettings, error) {
+	return c.GetAccountSettingsCtx(context.Background())
+}
 
+// GetAccountSettingsCtx is like GetAccountSettings, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountSettingsCtx(ctx context.Context) (*AccountSettings, error) {
+	if !c.IsAuthenticated() {
+		return nil, getAuthErr("Getting account settings requires an authenticated client")
+	}
+
+	body, rl, err := c.getURL(ctx, "account/"+AccountMe+"/settings")
 	if err != nil {
-		return nil, errors.New("Problem getting URL for rate - " + err.Error())
+		return nil, wrapErr(-1, "Problem getting URL for account settings", err)
 	}
-	//client.Log.Debugf("%v\n", body)
 
 	dec := json.NewDecoder(strings.NewReader(body))
-
-	var bodyDecoded rateLimitDataWrapper
-	if err := dec.Decode(&bodyDecoded); err != nil {
-		c.lastRateLimitErr = errors.New("Problem decoding json for ratelimit - " + err.Error())
-		return nil, c.lastRateLimitErr
+	var settings accountSettingsDataWrapper
+	if err := dec.Decode(&settings); err != nil {
+		return nil, getDecodeErr("Problem decoding json for account settings", err)
 	}
+	c.setLastRateLimit(rl)
 
-	if !bodyDecoded.Success {
-		c.lastRateLimitErr = errors.New("Request to imgur failed for ratelimit - " + strconv.Itoa(bodyDecoded.Status))
-		return nil, c.lastRateLimitErr
+	if !settings.Success || settings.Settings == nil {
+		return nil, getErr(settings.Status, "Request to imgur failed for account settings - "+strconv.Itoa(settings.Status))
 	}
 
-	var ret RateLimit
-	ret.ClientLimit = rl.ClientLimit
-	ret.ClientRemaining = rl.ClientRemaining
-	ret.UserLimit = rl.UserLimit
-	ret.UserRemaining = rl.UserRemaining
-	ret.UserReset = rl.UserReset
-	c.lastRateLimit = &ret
-	c.lastRateLimitErr = nil
+	settings.Settings.Limit = rl
+	return settings.Settings, nil
+}
 
-	return &ret, nil
+// UpdateAccountSettings updates the settings of the authenticated account.
+func (c *ImgurClient) UpdateAccountSettings(opts *AccountSettingsOptions) error {
+	return c.UpdateAccountSettingsCtx(context.Background(), opts)
 }
 
-// UploadImage uploads the image to imgur
-// image                Can be a binary file, base64 data, or a URL for an image. (up to 10MB)
-// album       optional The id of the album you want to add the image to.
-//                      For anonymous albums, album should be the deleteHash that is returned at creation.
-// dType                The type of the file that's being sent; file, base64 or URL
-// title       optional The title of the image.
-// description optional The description of the image.
-// returns image info, status code of the upload, error
-func (c *ImgurClient) UploadImage(image []byte, album, dType, title, description string) (*ImageInfo, error) {
-	if image == nil {
-		return nil, getErr(-1, "Invalid image")
+// UpdateAccountSettingsCtx is like UpdateAccountSettings, but uses the given context for its requests.
+func (c *ImgurClient) UpdateAccountSettingsCtx(ctx context.Context, opts *AccountSettingsOptions) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Updating account settings requires an authenticated client")
 	}
-	if dType != "file" && dType != "base64" && dType != "URL" {
-		return nil, getErr(-1, "Passed invalid dType: "+dType+". Please use file/base64/URL.")
+	if opts == nil {
+		return getValidationErr("Invalid account settings")
 	}
 
-	form := createUploadForm(image, album, dType, title, description)
-
-	URL := c.createAPIURL("image")
-	req, err := http.NewRequest("POST", URL, bytes.NewBufferString(form.Encode()))
-	// client.Log.Debugf("Posting to URL %v\n", URL)
+	body, rl, err := c.putURL(ctx, "account/"+AccountMe+"/settings", createAccountSettingsForm(opts))
 	if err != nil {
-		return nil, getErr(-1, "Could create request for "+URL+" - "+err.Error())
+		return wrapErr(-1, "Problem updating account settings", err)
 	}
 
-	req.Header.Add("Authorization", "Client-ID "+c.ImgurClientID)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	if c.RapidAPIKey != "" {
-		req.Header.Add("X-RapidAPI-Key", c.RapidAPIKey)
-	}
+	return c.decodeBasicResponse(body, rl, "updating account settings")
+}
+
+// GetAccountImages queries imgur for a page of the images of an account,
+// starting from page 0. Pass AccountMe or an empty username for the
+// authenticated account.
+func (c *ImgurClient) GetAccountImages(username string, page int) ([]ImageInfo, error) {
+	return c.GetAccountImagesCtx(context.Background(), username, page)
+}
 
-	res, err := c.HTTPClient.Do(req)
+// GetAccountImagesCtx is like GetAccountImages, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountImagesCtx(ctx context.Context, username string, page int) ([]ImageInfo, error) {
+	username, err := c.resolveUsername(username)
 	if err != nil {
-		return nil, getErr(-1, "Could not post "+URL+" - "+err.Error())
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	// Read the whole body
-	body, err := ioutil.ReadAll(res.Body)
+	images, _, err := c.getImageList(ctx, "account/"+username+"/images/"+strconv.Itoa(page),
+		"images of account "+username)
+	return images, err
+}
+
+// GetAccountAlbums queries imgur for a page of the albums of an account,
+// starting from page 0. Pass AccountMe or an empty username for the
+// authenticated account.
+func (c *ImgurClient) GetAccountAlbums(username string, page int) ([]AlbumInfo, error) {
+	return c.GetAccountAlbumsCtx(context.Background(), username, page)
+}
+
+// GetAccountAlbumsCtx is like GetAccountAlbums, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountAlbumsCtx(ctx context.Context, username string, page int) ([]AlbumInfo, error) {
+	username, err := c.resolveUsername(username)
 	if err != nil {
-		return nil, getErr(-1, "Problem reading the body of "+URL+" - "+err.Error())
+		return nil, err
 	}
 
-	// client.Log.Debugf("%v\n", string(body[:]))
+	albums, _, err := c.getAlbumList(ctx, "account/"+username+"/albums/"+strconv.Itoa(page),
+		"albums of account "+username)
+	return albums, err
+}
+
+// GetAccountAlbumIDs queries imgur for a page of the album ids of an account.
+func (c *ImgurClient) GetAccountAlbumIDs(username string, page int) ([]string, error) {
+	return c.GetAccountAlbumIDsCtx(context.Background(), username, page)
+}
 
-	dec := json.NewDecoder(bytes.NewReader(body))
-	var img imageInfoDataWrapper
-	if err = dec.Decode(&img); err != nil {
-		return nil, getErr(-1, "Problem decoding json result from image upload - "+err.Error()+". JSON(?): "+string(body))
+// GetAccountAlbumIDsCtx is like GetAccountAlbumIDs, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountAlbumIDsCtx(ctx context.Context, username string, page int) ([]string, error) {
+	username, err := c.resolveUsername(username)
+	if err != nil {
+		return nil, err
 	}
 
-	if !img.Success {
-		return nil, getErr(img.Status, "Upload to imgur failed with status: "+strconv.Itoa(img.Status))
+	ids, _, err := c.getIDList(ctx, "account/"+username+"/albums/ids/"+strconv.Itoa(page),
+		"album ids of account "+username)
+	return ids, err
+}
+
+// GetAccountAlbumCount queries imgur for the number of albums of an account.
+func (c *ImgurClient) GetAccountAlbumCount(username string) (int, error) {
+	return c.GetAccountAlbumCountCtx(context.Background(), username)
+}
+
+// GetAccountAlbumCountCtx is like GetAccountAlbumCount, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountAlbumCountCtx(ctx context.Context, username string) (int, error) {
+	username, err := c.resolveUsername(username)
+	if err != nil {
+		return 0, err
 	}
 
-	img.Info.Limit, _ = extractRateLimits(res.Header)
-	c.lastRateLimit = img.Info.Limit
+	return c.getCount(ctx, "account/"+username+"/albums/count", "album count of account "+username)
+}
 
-	return img.Info, nil
+// GetAccountImageIDs queries imgur for a page of the image ids of an account.
+func (c *ImgurClient) GetAccountImageIDs(username string, page int) ([]string, error) {
+	return c.GetAccountImageIDsCtx(context.Background(), username, page)
 }
 
-// UploadImageFromFile uploads a file given by the filename string to imgur.
-func (c *ImgurClient) UploadImageFromFile(filename, album, title, description string) (*ImageInfo, error) {
-	// client.Log.Infof("*** IMAGE UPLOAD ***\n")
-	f, err := os.Open(filename)
+// GetAccountImageIDsCtx is like GetAccountImageIDs, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountImageIDsCtx(ctx context.Context, username string, page int) ([]string, error) {
+	username, err := c.resolveUsername(username)
 	if err != nil {
-		return nil, getErrF(500, "Could not open file %v - Error: %v", filename, err)
+		return nil, err
 	}
-	defer f.Close()
-	fileInfo, err := f.Stat()
+
+	ids, _, err := c.getIDList(ctx, "account/"+username+"/images/ids/"+strconv.Itoa(page),
+		"image ids of account "+username)
+	return ids, err
+}
+
+// GetAccountImageCount queries imgur for the number of images of an account.
+func (c *ImgurClient) GetAccountImageCount(username string) (int, error) {
+	return c.GetAccountImageCountCtx(context.Background(), username)
+}
+
+// GetAccountImageCountCtx is like GetAccountImageCount, but uses the given context for its requests.
+func (c *ImgurClient) GetAccountImageCountCtx(ctx context.Context, username string) (int, error) {
+	username, err := c.resolveUsername(username)
 	if err != nil {
-		return nil, getErrF(500, "Could not stat file %v - Error: %v", filename, err)
+		return 0, err
 	}
-	size := fileInfo.Size()
-	b := make([]byte, size)
-	n, err := f.Read(b)
-	if err != nil || int64(n) != size {
-		return nil, getErrF(500, "Could not read file %v - Error: %v", filename, err)
+
+	return c.getCount(ctx, "account/"+username+"/images/count", "image count of account "+username)
+}
+
+// resolveUsername returns the username to be used in account urls,
+// making sure the client is authenticated when referring to itself.
+func (c *ImgurClient) resolveUsername(username string) (string, error) {
+	if username == "" {
+		username = AccountMe
+	}
+
+	if username == AccountMe && !c.IsAuthenticated() {
+		return "", getAuthErr("Referring to the current account requires an authenticated client")
 	}
 
-	return c.UploadImage(b, album, "file", title, description)
+	return url.PathEscape(username), nil
 }
 
-// --------------------------------------------------------
+// AccountImagesPager returns a Pager over all images of an account.
+// Pass AccountMe or an empty username for the authenticated account;
+// maxItems <= 0 means no limit.
+func (c *ImgurClient) AccountImagesPager(username string, maxItems int) *Pager[ImageInfo] {
+	return NewPager(func(ctx context.Context, page int) ([]ImageInfo, *RateLimit, error) {
+		resolved, err := c.resolveUsername(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.getImageList(ctx, "account/"+resolved+"/images/"+strconv.Itoa(page),
+			"images of account "+resolved)
+	}, maxItems)
+}
 
-func (e *ImgurError) Error() string {
-	myStr := ""
-	if e.Status != 0 {
-		myStr += "[" + ssg.ToBase10(int64(e.Status)) + "] : "
+// AccountAlbumsPager returns a Pager over all albums of an account.
+// Pass AccountMe or an empty username for the authenticated account;
+// maxItems <= 0 means no limit.
+func (c *ImgurClient) AccountAlbumsPager(username string, maxItems int) *Pager[AlbumInfo] {
+	return NewPager(func(ctx context.Context, page int) ([]AlbumInfo, *RateLimit, error) {
+		resolved, err := c.resolveUsername(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.getAlbumList(ctx, "account/"+resolved+"/albums/"+strconv.Itoa(page),
+			"albums of account "+resolved)
+	}, maxItems)
+}
+
+// AccountImageIDsPager returns a Pager over all image ids of an account.
+// Pass AccountMe or an empty username for the authenticated account;
+// maxItems <= 0 means no limit.
+func (c *ImgurClient) AccountImageIDsPager(username string, maxItems int) *Pager[string] {
+	return NewPager(func(ctx context.Context, page int) ([]string, *RateLimit, error) {
+		resolved, err := c.resolveUsername(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.getIDList(ctx, "account/"+resolved+"/images/ids/"+strconv.Itoa(page),
+			"image ids of account "+resolved)
+	}, maxItems)
+}
+
+// AccountAlbumIDsPager returns a Pager over all album ids of an account.
+// Pass AccountMe or an empty username for the authenticated account;
+// maxItems <= 0 means no limit.
+func (c *ImgurClient) AccountAlbumIDsPager(username string, maxItems int) *Pager[string] {
+	return NewPager(func(ctx context.Context, page int) ([]string, *RateLimit, error) {
+		resolved, err := c.resolveUsername(username)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c.getIDList(ctx, "account/"+resolved+"/albums/ids/"+strconv.Itoa(page),
+			"album ids of account "+resolved)
+	}, maxItems)
+}
+
+// getAlbumList queries imgur for a list of albums,
+// attaching the rate limit to each of them.
+func (c *ImgurClient) getAlbumList(ctx context.Context, theUrl, what string) ([]AlbumInfo, *RateLimit, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return nil, nil, wrapErr(-1, "Problem getting URL for "+what, err)
 	}
 
-	if e.Message != "" {
-		myStr += e.Message + " : "
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list albumListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, nil, getDecodeErr("Problem decoding json for "+what, err)
 	}
+	c.setLastRateLimit(rl)
 
-	if e.Err != nil {
-		myStr += e.Err.Error()
+	if !list.Success {
+		return nil, nil, getErr(list.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(list.Status))
 	}
 
-	myStr = strings.Trim(strings.TrimSpace(myStr), ":")
+	for i := range list.Albums {
+		list.Albums[i].Limit = rl
+	}
+
+	return list.Albums, rl, nil
+}
+
+func (c *ImgurClient) getIDList(ctx context.Context, theUrl, what string) ([]string, *RateLimit, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return nil, nil, wrapErr(-1, "Problem getting URL for "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list idListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, nil, getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !list.Success {
+		return nil, nil, getErr(list.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(list.Status))
+	}
+
+	return list.IDs, rl, nil
+}
+
+func (c *ImgurClient) getCount(ctx context.Context, theUrl, what string) (int, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return 0, wrapErr(-1, "Problem getting URL for "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var count countDataWrapper
+	if err := dec.Decode(&count); err != nil {
+		return 0, getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !count.Success {
+		return 0, getErr(count.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(count.Status))
+	}
+
+	return count.Count, nil
+}
+
+// GetInfoFromURL tries to query imgur based on information identified in the URL.
+// returns image/album info, status code of the request, error
+func (c *ImgurClient) GetInfoFromURL(url string) (*GenericInfo, error) {
+	return c.GetInfoFromURLCtx(context.Background(), url)
+}
+
+// GetInfoFromURLCtx is like GetInfoFromURL, but uses the given context for its requests.
+func (c *ImgurClient) GetInfoFromURLCtx(ctx context.Context, url string) (*GenericInfo, error) {
+	url = strings.TrimSpace(url)
+
+	// https://i.imgur.com/<id>.jpg -> image
+	if strings.Contains(url, "://i.imgur.com/") {
+		return c.directImageURL(ctx, url)
+	}
+
+	// https://imgur.com/a/<id> -> album
+	if strings.Contains(url, "://imgur.com/a/") || strings.Contains(url, "://m.imgur.com/a/") {
+		return c.albumURL(ctx, url)
+	}
+
+	// https://imgur.com/gallery/<id> -> gallery album
+	if strings.Contains(url, "://imgur.com/gallery/") || strings.Contains(url, "://m.imgur.com/gallery/") {
+		return c.galleryURL(ctx, url)
+	}
+
+	// https://imgur.com/<id> -> image
+	if strings.Contains(url, "://imgur.com/") || strings.Contains(url, "://m.imgur.com/") {
+		return c.imageURL(ctx, url)
+	}
+
+	c.log(ctx, slog.LevelDebug, "no imgur url pattern matched", "url", url)
+	return nil, getValidationErr("URL pattern matching for URL " + url + " failed.")
+}
+
+func (c *ImgurClient) directImageURL(ctx context.Context, url string) (*GenericInfo, error) {
+	var ret GenericInfo
+	start := strings.LastIndex(url, "/") + 1
+	end := strings.LastIndex(url, ".")
+	if start+1 >= end {
+		return nil, getValidationErr("Could not find ID in URL " + url + ". I was going down i.imgur.com path.")
+	}
+	id := url[start:end]
+	c.log(ctx, slog.LevelDebug, "detected imgur image url", "id", id, "pattern", "i.imgur.com/")
+	gii, err := c.GetGalleryImageInfoCtx(ctx, id)
+	if err == nil {
+		ret.GImage = gii
+	} else {
+		var ii *ImageInfo
+		ii, err = c.GetImageInfoCtx(ctx, id)
+		ret.Image = ii
+	}
+	return &ret, err
+}
+
+func (c *ImgurClient) albumURL(ctx context.Context, url string) (*GenericInfo, error) {
+	var ret GenericInfo
+
+	start := strings.LastIndex(url, "/") + 1
+	end := strings.LastIndex(url, "?")
+	if end == -1 {
+		end = len(url)
+	}
+	id := url[start:end]
+	if id == "" {
+		return nil, getValidationErr("Could not find ID in URL " + url + ". I was going down imgur.com/a/ path.")
+	}
+	c.log(ctx, slog.LevelDebug, "detected imgur album url", "id", id, "pattern", "imgur.com/a/")
+	ai, err := c.GetAlbumInfoCtx(ctx, id)
+	ret.Album = ai
+	return &ret, err
+}
+
+func (c *ImgurClient) galleryURL(ctx context.Context, url string) (*GenericInfo, error) {
+	var ret GenericInfo
+
+	start := strings.LastIndex(url, "/") + 1
+	end := strings.LastIndex(url, "?")
+	if end == -1 {
+		end = len(url)
+	}
+	id := url[start:end]
+	if id == "" {
+		return nil, getValidationErr("Could not find ID in URL " + url + ". I was going down imgur.com/gallery/ path.")
+	}
+
+	c.log(ctx, slog.LevelDebug, "detected imgur gallery url", "id", id, "pattern", "imgur.com/gallery/")
+	ai, err := c.GetGalleryAlbumInfoCtx(ctx, id)
+	if err == nil {
+		ret.GAlbum = ai
+		return &ret, err
+	}
+	// fallback to GetGalleryImageInfo
+	c.log(ctx, slog.LevelDebug, "imgur gallery album not found, trying gallery image", "id", id, "error", err)
+	ii, err := c.GetGalleryImageInfoCtx(ctx, id)
+	ret.GImage = ii
+	return &ret, err
+}
+
+func (c *ImgurClient) imageURL(ctx context.Context, url string) (*GenericInfo, error) {
+	var ret GenericInfo
+
+	start := strings.LastIndex(url, "/") + 1
+	end := strings.LastIndex(url, "?")
+	if end == -1 {
+		end = len(url)
+	}
+	id := url[start:end]
+	if id == "" {
+		return nil, getValidationErr("Could not find ID in URL " + url + ". I was going down imgur.com/ path.")
+	}
+	c.log(ctx, slog.LevelDebug, "detected imgur image url", "id", id, "pattern", "imgur.com/")
+	ii, err := c.GetGalleryImageInfoCtx(ctx, id)
+	if err == nil {
+		ret.GImage = ii
+		return &ret, nil
+	}
+
+	i, err := c.GetImageInfoCtx(ctx, id)
+	ret.Image = i
+	return &ret, err
+}
+
+// GetGallery queries imgur for a page of the gallery, starting from page 0.
+// Empty section, sort and window default to hot, viral and day.
+// showViral shows or hides viral images in the user section, mature shows
+// or hides mature content and albumPreviews includes the images of albums.
+func (c *ImgurClient) GetGallery(section GallerySection, sort GallerySort, window GalleryWindow,
+	page int, showViral, mature, albumPreviews bool) ([]GalleryItem, error) {
+	return c.GetGalleryCtx(context.Background(), section, sort, window, page, showViral, mature, albumPreviews)
+}
+
+// GetGalleryCtx is like GetGallery, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryCtx(ctx context.Context, section GallerySection, sort GallerySort,
+	window GalleryWindow, page int, showViral, mature, albumPreviews bool) ([]GalleryItem, error) {
+	items, _, err := c.getGalleryList(ctx, createGalleryPath(section, sort, window, page)+"?"+
+		createGalleryQuery(showViral, mature, albumPreviews), "gallery "+string(section))
+	return items, err
+}
+
+// GalleryPager returns a Pager over the gallery, see GetGallery for the parameters.
+// maxItems <= 0 means no limit.
+func (c *ImgurClient) GalleryPager(section GallerySection, sort GallerySort, window GalleryWindow,
+	showViral, mature, albumPreviews bool, maxItems int) *Pager[GalleryItem] {
+	return NewPager(func(ctx context.Context, page int) ([]GalleryItem, *RateLimit, error) {
+		return c.getGalleryList(ctx, createGalleryPath(section, sort, window, page)+"?"+
+			createGalleryQuery(showViral, mature, albumPreviews), "gallery "+string(section))
+	}, maxItems)
+}
+
+// SearchGallery searches the gallery with the given query.
+func (c *ImgurClient) SearchGallery(query *SearchQuery) ([]GalleryItem, error) {
+	return c.SearchGalleryCtx(context.Background(), query)
+}
+
+// SearchGalleryCtx is like SearchGallery, but uses the given context for its requests.
+func (c *ImgurClient) SearchGalleryCtx(ctx context.Context, query *SearchQuery) ([]GalleryItem, error) {
+	if query == nil || query.IsEmpty() {
+		return nil, getValidationErr("Invalid search query")
+	}
+
+	items, _, err := c.getGalleryList(ctx, query.createURL(query.page), "gallery search "+query.String())
+	return items, err
+}
+
+// SearchGalleryPager returns a Pager over the results of a gallery search,
+// starting from the page of the query. maxItems <= 0 means no limit.
+func (c *ImgurClient) SearchGalleryPager(query *SearchQuery, maxItems int) *Pager[GalleryItem] {
+	return NewPager(func(ctx context.Context, page int) ([]GalleryItem, *RateLimit, error) {
+		if query == nil || query.IsEmpty() {
+			return nil, nil, getValidationErr("Invalid search query")
+		}
+		return c.getGalleryList(ctx, query.createURL(query.page+page), "gallery search "+query.String())
+	}, maxItems)
+}
+
+// getGalleryList queries imgur for a list of gallery items,
+// attaching the rate limit to each of them.
+func (c *ImgurClient) getGalleryList(ctx context.Context, theUrl, what string) ([]GalleryItem, *RateLimit, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return nil, nil, wrapErr(-1, "Problem getting URL for "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list galleryListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, nil, getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !list.Success {
+		return nil, nil, getErr(list.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(list.Status))
+	}
+
+	for i := range list.Items {
+		list.Items[i].setLimit(rl)
+	}
+
+	return list.Items, rl, nil
+}
+
+// GetGalleryItem queries imgur for a gallery item, which is either an image or an album.
+func (c *ImgurClient) GetGalleryItem(id string) (*GalleryItem, error) {
+	return c.GetGalleryItemCtx(context.Background(), id)
+}
+
+// GetGalleryItemCtx is like GetGalleryItem, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryItemCtx(ctx context.Context, id string) (*GalleryItem, error) {
+	if id == "" {
+		return nil, getValidationErr("Invalid gallery id")
+	}
+
+	body, rl, err := c.getURL(ctx, "gallery/"+id)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for gallery ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var item galleryItemDataWrapper
+	if err := dec.Decode(&item); err != nil {
+		return nil, getDecodeErr("Problem decoding json for gallery ID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !item.Success || item.Item == nil {
+		return nil, getErr(item.Status, "Request to imgur failed for gallery ID "+id+" - "+strconv.Itoa(item.Status))
+	}
+
+	item.Item.setLimit(rl)
+	return item.Item, nil
+}
+
+// SubmitToGallery shares an image or album of the authenticated user with
+// the public gallery and returns it as a gallery item. title is required;
+// terms has to be true if the user hasn't accepted the gallery terms yet.
+// topic and tags are optional.
+func (c *ImgurClient) SubmitToGallery(id, title, topic string, terms, mature bool, tags []string) (*GalleryItem, error) {
+	return c.SubmitToGalleryCtx(context.Background(), id, title, topic, terms, mature, tags)
+}
+
+// SubmitToGalleryCtx is like SubmitToGallery, but uses the given context for its requests.
+func (c *ImgurClient) SubmitToGalleryCtx(ctx context.Context, id, title, topic string, terms, mature bool, tags []string) (*GalleryItem, error) {
+	if !c.IsAuthenticated() {
+		return nil, getAuthErr("Submitting to the gallery requires an authenticated client")
+	}
+	if id == "" || title == "" {
+		return nil, getValidationErr("Invalid id or title for gallery submission")
+	}
+
+	what := "submitting ID " + id + " to the gallery"
+	body, rl, err := c.postURL(ctx, "gallery/"+id, createGallerySubmitForm(title, topic, terms, mature, tags))
+	if err != nil {
+		return nil, wrapErr(-1, "Problem "+what, err)
+	}
+
+	if err = c.decodeBasicResponse(body, rl, what); err != nil {
+		return nil, err
+	}
+
+	return c.GetGalleryItemCtx(ctx, id)
+}
+
+// RemoveFromGallery removes an image or album of the authenticated user
+// from the public gallery, without deleting it.
+func (c *ImgurClient) RemoveFromGallery(id string) error {
+	return c.RemoveFromGalleryCtx(context.Background(), id)
+}
+
+// RemoveFromGalleryCtx is like RemoveFromGallery, but uses the given context for its requests.
+func (c *ImgurClient) RemoveFromGalleryCtx(ctx context.Context, id string) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Removing from the gallery requires an authenticated client")
+	}
+	if id == "" {
+		return getValidationErr("Invalid gallery id")
+	}
+
+	what := "removing gallery ID " + id
+	body, rl, err := c.deleteURL(ctx, "gallery/"+id)
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// VoteGallery votes on a gallery item. Requires the client to be authenticated.
+func (c *ImgurClient) VoteGallery(id string, vote Vote) error {
+	return c.VoteGalleryCtx(context.Background(), id, vote)
+}
+
+// VoteGalleryCtx is like VoteGallery, but uses the given context for its requests.
+func (c *ImgurClient) VoteGalleryCtx(ctx context.Context, id string, vote Vote) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Voting requires an authenticated client")
+	}
+	if !isValidVote(vote) {
+		return getValidationErr("Passed invalid vote: " + string(vote) + ". Please use up/down/veto.")
+	}
+	if id == "" {
+		return getValidationErr("Invalid gallery id")
+	}
+
+	what := "voting on gallery ID " + id
+	body, rl, err := c.postURL(ctx, "gallery/"+id+"/vote/"+string(vote), url.Values{})
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// GetGalleryVotes queries imgur for the vote counts of a gallery item.
+func (c *ImgurClient) GetGalleryVotes(id string) (*GalleryVotes, error) {
+	return c.GetGalleryVotesCtx(context.Background(), id)
+}
+
+// GetGalleryVotesCtx is like GetGalleryVotes, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryVotesCtx(ctx context.Context, id string) (*GalleryVotes, error) {
+	if id == "" {
+		return nil, getValidationErr("Invalid gallery id")
+	}
+
+	body, rl, err := c.getURL(ctx, "gallery/"+id+"/votes")
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for votes of gallery ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var votes galleryVotesDataWrapper
+	if err := dec.Decode(&votes); err != nil {
+		return nil, getDecodeErr("Problem decoding json for votes of gallery ID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !votes.Success || votes.Votes == nil {
+		return nil, getErr(votes.Status, "Request to imgur failed for votes of gallery ID "+id+" - "+strconv.Itoa(votes.Status))
+	}
+
+	votes.Votes.Limit = rl
+	return votes.Votes, nil
+}
+
+// FavoriteAlbum toggles the favorite state of an album for the current user.
+// It returns true if the album is now favorited, false if it has been unfavorited.
+// Requires the client to be authenticated.
+func (c *ImgurClient) FavoriteAlbum(id string) (bool, error) {
+	return c.FavoriteAlbumCtx(context.Background(), id)
+}
+
+// FavoriteAlbumCtx is like FavoriteAlbum, but uses the given context for its requests.
+func (c *ImgurClient) FavoriteAlbumCtx(ctx context.Context, id string) (bool, error) {
+	return c.toggleFavorite(ctx, "album/"+id+"/favorite", "album "+id)
+}
+
+// GetDefaultTopics queries imgur for the default gallery topics.
+func (c *ImgurClient) GetDefaultTopics() ([]Topic, error) {
+	return c.GetDefaultTopicsCtx(context.Background())
+}
+
+// GetDefaultTopicsCtx is like GetDefaultTopics, but uses the given context for its requests.
+func (c *ImgurClient) GetDefaultTopicsCtx(ctx context.Context) ([]Topic, error) {
+	body, rl, err := c.getURL(ctx, "topics/defaults")
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for default topics", err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list topicListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, getDecodeErr("Problem decoding json for default topics", err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !list.Success {
+		return nil, getErr(list.Status, "Request to imgur failed for default topics - "+strconv.Itoa(list.Status))
+	}
+
+	return list.Topics, nil
+}
+
+// GetTopicGallery queries imgur for a page of the gallery items of a topic,
+// starting from page 0. topicID is either the id or the name of the topic.
+// Empty sort and window default to viral and week.
+func (c *ImgurClient) GetTopicGallery(topicID string, sort GallerySort, window GalleryWindow, page int) ([]GalleryItem, error) {
+	return c.GetTopicGalleryCtx(context.Background(), topicID, sort, window, page)
+}
+
+// GetTopicGalleryCtx is like GetTopicGallery, but uses the given context for its requests.
+func (c *ImgurClient) GetTopicGalleryCtx(ctx context.Context, topicID string, sort GallerySort, window GalleryWindow, page int) ([]GalleryItem, error) {
+	if topicID == "" {
+		return nil, getValidationErr("Invalid topic id")
+	}
+
+	items, _, err := c.getGalleryList(ctx, createGalleryListPath("topics/"+url.PathEscape(topicID), sort, window, page),
+		"gallery of topic "+topicID)
+	return items, err
+}
+
+// GetTags queries imgur for the default tags.
+func (c *ImgurClient) GetTags() (*TagsInfo, error) {
+	return c.GetTagsCtx(context.Background())
+}
+
+// GetTagsCtx is like GetTags, but uses the given context for its requests.
+func (c *ImgurClient) GetTagsCtx(ctx context.Context) (*TagsInfo, error) {
+	body, rl, err := c.getURL(ctx, "tags")
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for tags", err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var tags tagsInfoDataWrapper
+	if err := dec.Decode(&tags); err != nil {
+		return nil, getDecodeErr("Problem decoding json for tags", err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !tags.Success || tags.Info == nil {
+		return nil, getErr(tags.Status, "Request to imgur failed for tags - "+strconv.Itoa(tags.Status))
+	}
+
+	tags.Info.Limit = rl
+	return tags.Info, nil
+}
+
+// GetTag queries imgur for a tag along with a page of its gallery items,
+// starting from page 0. Empty sort and window default to viral and week.
+func (c *ImgurClient) GetTag(name string, sort GallerySort, window GalleryWindow, page int) (*Tag, error) {
+	return c.GetTagCtx(context.Background(), name, sort, window, page)
+}
+
+// GetTagCtx is like GetTag, but uses the given context for its requests.
+func (c *ImgurClient) GetTagCtx(ctx context.Context, name string, sort GallerySort, window GalleryWindow, page int) (*Tag, error) {
+	if name == "" {
+		return nil, getValidationErr("Invalid tag name")
+	}
+
+	body, rl, err := c.getURL(ctx, createGalleryListPath("gallery/t/"+url.PathEscape(name), sort, window, page))
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for tag "+name, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var tag tagDataWrapper
+	if err := dec.Decode(&tag); err != nil {
+		return nil, getDecodeErr("Problem decoding json for tag "+name, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !tag.Success || tag.Tag == nil {
+		return nil, getErr(tag.Status, "Request to imgur failed for tag "+name+" - "+strconv.Itoa(tag.Status))
+	}
+
+	for i := range tag.Tag.Items {
+		tag.Tag.Items[i].setLimit(rl)
+	}
+
+	tag.Tag.Limit = rl
+	return tag.Tag, nil
+}
+
+// GetGalleryItemTags queries imgur for the tags of a gallery item along with their votes.
+func (c *ImgurClient) GetGalleryItemTags(id string) ([]TagVote, error) {
+	return c.GetGalleryItemTagsCtx(context.Background(), id)
+}
+
+// GetGalleryItemTagsCtx is like GetGalleryItemTags, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryItemTagsCtx(ctx context.Context, id string) ([]TagVote, error) {
+	if id == "" {
+		return nil, getValidationErr("Invalid gallery id")
+	}
+
+	body, rl, err := c.getURL(ctx, "gallery/"+id+"/tags")
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for tags of gallery ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var votes tagVotesDataWrapper
+	if err := dec.Decode(&votes); err != nil {
+		return nil, getDecodeErr("Problem decoding json for tags of gallery ID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !votes.Success || votes.Data == nil {
+		return nil, getErr(votes.Status, "Request to imgur failed for tags of gallery ID "+id+" - "+strconv.Itoa(votes.Status))
+	}
+
+	return votes.Data.Tags, nil
+}
+
+// VoteTag votes on a tag of a gallery item. Only VoteUp and VoteDown
+// are supported. Requires the client to be authenticated.
+func (c *ImgurClient) VoteTag(id, tag string, vote Vote) error {
+	return c.VoteTagCtx(context.Background(), id, tag, vote)
+}
+
+// VoteTagCtx is like VoteTag, but uses the given context for its requests.
+func (c *ImgurClient) VoteTagCtx(ctx context.Context, id, tag string, vote Vote) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Voting requires an authenticated client")
+	}
+	if vote != VoteUp && vote != VoteDown {
+		return getValidationErr("Passed invalid tag vote: " + string(vote) + ". Please use up/down.")
+	}
+	if id == "" || tag == "" {
+		return getValidationErr("Invalid gallery id or tag")
+	}
+
+	what := "voting on tag " + tag + " of gallery ID " + id
+	body, rl, err := c.postURL(ctx, "gallery/"+id+"/vote/tag/"+url.PathEscape(tag)+"/"+string(vote), url.Values{})
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// UpdateGalleryTags replaces the tags of a gallery item of the authenticated user.
+func (c *ImgurClient) UpdateGalleryTags(id string, tags []string) error {
+	return c.UpdateGalleryTagsCtx(context.Background(), id, tags)
+}
+
+// UpdateGalleryTagsCtx is like UpdateGalleryTags, but uses the given context for its requests.
+func (c *ImgurClient) UpdateGalleryTagsCtx(ctx context.Context, id string, tags []string) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Updating tags requires an authenticated client")
+	}
+	if id == "" || len(tags) == 0 {
+		return getValidationErr("Invalid gallery id or tags")
+	}
+
+	form := url.Values{}
+	form.Add("tags", strings.Join(tags, ","))
+
+	what := "updating tags of gallery ID " + id
+	body, rl, err := c.postURL(ctx, "gallery/tags/"+id, form)
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// GetGalleryComments queries imgur for the comments of a gallery item,
+// including their replies. An empty sort defaults to best.
+func (c *ImgurClient) GetGalleryComments(id string, sort CommentSort) ([]Comment, error) {
+	return c.GetGalleryCommentsCtx(context.Background(), id, sort)
+}
+
+// GetGalleryCommentsCtx is like GetGalleryComments, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryCommentsCtx(ctx context.Context, id string, sort CommentSort) ([]Comment, error) {
+	if id == "" {
+		return nil, getValidationErr("Invalid gallery id")
+	}
+	if sort == "" {
+		sort = CommentSortBest
+	}
+
+	body, rl, err := c.getURL(ctx, "gallery/"+id+"/comments/"+string(sort))
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for comments of gallery ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var list commentListDataWrapper
+	if err := dec.Decode(&list); err != nil {
+		return nil, getDecodeErr("Problem decoding json for comments of gallery ID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !list.Success {
+		return nil, getErr(list.Status, "Request to imgur failed for comments of gallery ID "+id+" - "+strconv.Itoa(list.Status))
+	}
+
+	return list.Comments, nil
+}
+
+// GetComment queries imgur for a comment.
+func (c *ImgurClient) GetComment(id int) (*Comment, error) {
+	return c.GetCommentCtx(context.Background(), id)
+}
+
+// GetCommentCtx is like GetComment, but uses the given context for its requests.
+func (c *ImgurClient) GetCommentCtx(ctx context.Context, id int) (*Comment, error) {
+	return c.getComment(ctx, "comment/"+strconv.Itoa(id), "commentID "+strconv.Itoa(id))
+}
+
+// GetCommentReplies queries imgur for a comment along with all of its replies,
+// which are found in its Children.
+func (c *ImgurClient) GetCommentReplies(id int) (*Comment, error) {
+	return c.GetCommentRepliesCtx(context.Background(), id)
+}
+
+// GetCommentRepliesCtx is like GetCommentReplies, but uses the given context for its requests.
+func (c *ImgurClient) GetCommentRepliesCtx(ctx context.Context, id int) (*Comment, error) {
+	return c.getComment(ctx, "comment/"+strconv.Itoa(id)+"/replies", "replies of commentID "+strconv.Itoa(id))
+}
+
+func (c *ImgurClient) getComment(ctx context.Context, theUrl, what string) (*Comment, error) {
+	body, rl, err := c.getURL(ctx, theUrl)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var comment commentDataWrapper
+	if err := dec.Decode(&comment); err != nil {
+		return nil, getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !comment.Success || comment.Comment == nil {
+		return nil, getErr(comment.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(comment.Status))
+	}
+
+	return comment.Comment, nil
+}
+
+// PostComment comments on an image or gallery item and returns the id of the
+// new comment. Requires the client to be authenticated.
+func (c *ImgurClient) PostComment(imageID, comment string) (int, error) {
+	return c.PostCommentCtx(context.Background(), imageID, comment)
+}
+
+// PostCommentCtx is like PostComment, but uses the given context for its requests.
+func (c *ImgurClient) PostCommentCtx(ctx context.Context, imageID, comment string) (int, error) {
+	return c.postComment(ctx, "comment", imageID, comment, "posting comment on imageID "+imageID)
+}
+
+// ReplyToComment replies to a comment and returns the id of the new comment.
+// imageID is the id of the image or gallery item the parent comment is on.
+// Requires the client to be authenticated.
+func (c *ImgurClient) ReplyToComment(parentID int, imageID, comment string) (int, error) {
+	return c.ReplyToCommentCtx(context.Background(), parentID, imageID, comment)
+}
+
+// ReplyToCommentCtx is like ReplyToComment, but uses the given context for its requests.
+func (c *ImgurClient) ReplyToCommentCtx(ctx context.Context, parentID int, imageID, comment string) (int, error) {
+	return c.postComment(ctx, "comment/"+strconv.Itoa(parentID), imageID, comment,
+		"replying to commentID "+strconv.Itoa(parentID))
+}
+
+func (c *ImgurClient) postComment(ctx context.Context, theUrl, imageID, comment, what string) (int, error) {
+	if !c.IsAuthenticated() {
+		return 0, getAuthErr("Commenting requires an authenticated client")
+	}
+	if imageID == "" || comment == "" {
+		return 0, getValidationErr("Invalid image id or comment for " + what)
+	}
+
+	form := url.Values{}
+	form.Add("image_id", imageID)
+	form.Add("comment", comment)
+
+	body, rl, err := c.postURL(ctx, theUrl, form)
+	if err != nil {
+		return 0, wrapErr(-1, "Problem "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var created commentIDDataWrapper
+	if err := dec.Decode(&created); err != nil {
+		return 0, getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !created.Success || created.Data == nil {
+		return 0, getErr(created.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(created.Status))
+	}
+
+	return created.Data.ID, nil
+}
+
+// VoteComment votes on a comment. Requires the client to be authenticated.
+func (c *ImgurClient) VoteComment(id int, vote Vote) error {
+	return c.VoteCommentCtx(context.Background(), id, vote)
+}
+
+// VoteCommentCtx is like VoteComment, but uses the given context for its requests.
+func (c *ImgurClient) VoteCommentCtx(ctx context.Context, id int, vote Vote) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Voting requires an authenticated client")
+	}
+	if !isValidVote(vote) {
+		return getValidationErr("Passed invalid vote: " + string(vote) + ". Please use up/down/veto.")
+	}
+
+	what := "voting on commentID " + strconv.Itoa(id)
+	body, rl, err := c.postURL(ctx, "comment/"+strconv.Itoa(id)+"/vote/"+string(vote), url.Values{})
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// DeleteComment deletes a comment of the authenticated user.
+func (c *ImgurClient) DeleteComment(id int) error {
+	return c.DeleteCommentCtx(context.Background(), id)
+}
+
+// DeleteCommentCtx is like DeleteComment, but uses the given context for its requests.
+func (c *ImgurClient) DeleteCommentCtx(ctx context.Context, id int) error {
+	if !c.IsAuthenticated() {
+		return getAuthErr("Deleting a comment requires an authenticated client")
+	}
+
+	what := "deleting commentID " + strconv.Itoa(id)
+	body, rl, err := c.deleteURL(ctx, "comment/"+strconv.Itoa(id))
+	if err != nil {
+		return wrapErr(-1, "Problem "+what, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, what)
+}
+
+// GetGalleryAlbumInfo queries imgur for information on a gallery album
+// returns album info, status code of the request, error
+func (c *ImgurClient) GetGalleryAlbumInfo(id string) (*GalleryAlbumInfo, error) {
+	return c.GetGalleryAlbumInfoCtx(context.Background(), id)
+}
+
+// GetGalleryAlbumInfoCtx is like GetGalleryAlbumInfo, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryAlbumInfoCtx(ctx context.Context, id string) (*GalleryAlbumInfo, error) {
+	body, rl, err := c.getURL(ctx, "gallery/album/"+id)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for gallery album info ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var alb galleryAlbumInfoDataWrapper
+	if err := dec.Decode(&alb); err != nil {
+		return nil, getDecodeErr("Problem decoding json for gallery albumID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+	if !alb.Success {
+		return nil, getErr(alb.Status, "Request to imgur failed for gallery albumID "+id+" - "+strconv.Itoa(alb.Status))
+	}
+	if alb.Ai == nil {
+		return nil, getDecodeErr("Imgur returned no data for gallery albumID "+id, nil)
+	}
+
+	alb.Ai.Limit = rl
+	return alb.Ai, nil
+}
+
+// GetGalleryImageInfo queries imgur for information on a image
+// returns image info, status code of the request, error
+func (c *ImgurClient) GetGalleryImageInfo(id string) (*GalleryImageInfo, error) {
+	return c.GetGalleryImageInfoCtx(context.Background(), id)
+}
+
+// GetGalleryImageInfoCtx is like GetGalleryImageInfo, but uses the given context for its requests.
+func (c *ImgurClient) GetGalleryImageInfoCtx(ctx context.Context, id string) (*GalleryImageInfo, error) {
+	body, rl, err := c.getURL(ctx, "gallery/image/"+id)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for gallery image info ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var img galleryImageInfoDataWrapper
+	if err := dec.Decode(&img); err != nil {
+		return nil, getDecodeErr("Problem decoding json for gallery imageID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+	if !img.Success {
+		return nil, getErr(img.Status, "Request to imgur failed for gallery imageID "+id+" - "+strconv.Itoa(img.Status))
+	}
+	if img.Ii == nil {
+		return nil, getDecodeErr("Imgur returned no data for gallery imageID "+id, nil)
+	}
+
+	img.Ii.Limit = rl
+	return img.Ii, nil
+}
+
+func (c *ImgurClient) createAPIURL(u string) string {
+	if c.baseURL != "" {
+		return c.baseURL + u
+	}
+	if c.RapidAPIKey == "" {
+		return apiEndpoint + u
+	}
+	return apiEndpointRapidAPI + u
+}
+
+// createUploadURL returns the url of the upload endpoint, which is
+// relative to the upload url of the client if it has been set.
+func (c *ImgurClient) createUploadURL(u string) string {
+	if c.uploadURL != "" {
+		return c.uploadURL + u
+	}
+	return c.createAPIURL(u)
+}
+
+// GetLastRateLimit returns a snapshot of the rate limit of the last request,
+// along with the error of parsing it, if any.
+func (c *ImgurClient) GetLastRateLimit() (*RateLimit, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.lastRateLimit == nil {
+		return nil, c.lastRateLimitErr
+	}
+
+	snapshot := *c.lastRateLimit
+	return &snapshot, c.lastRateLimitErr
+}
+
+func (c *ImgurClient) setLastRateLimit(rl *RateLimit) {
+	c.mut.Lock()
+	c.lastRateLimit = rl
+	c.mut.Unlock()
+}
+
+func (c *ImgurClient) setLastRateLimitErr(err error) {
+	c.mut.Lock()
+	c.lastRateLimitErr = err
+	c.mut.Unlock()
+}
+
+// getURL returns
+// - body as string
+// - RateLimit with current limits
+// - error in case something broke
+func (c *ImgurClient) getURL(ctx context.Context, theUrl string) (string, *RateLimit, error) {
+	return c.sendRequest(ctx, "GET", theUrl, nil)
+}
+
+// postURL sends the form to the url, see getURL for the returned values.
+func (c *ImgurClient) postURL(ctx context.Context, theUrl string, form url.Values) (string, *RateLimit, error) {
+	return c.sendRequest(ctx, "POST", theUrl, form)
+}
+
+// putURL sends the form to the url, see getURL for the returned values.
+func (c *ImgurClient) putURL(ctx context.Context, theUrl string, form url.Values) (string, *RateLimit, error) {
+	return c.sendRequest(ctx, "PUT", theUrl, form)
+}
+
+// deleteURL sends a DELETE request to the url, see getURL for the returned values.
+func (c *ImgurClient) deleteURL(ctx context.Context, theUrl string) (string, *RateLimit, error) {
+	return c.sendRequest(ctx, "DELETE", theUrl, nil)
+}
+
+// sendRequest sends a request with the given method to the url. If form is
+// not nil, it's sent url-encoded as the body of the request.
+func (c *ImgurClient) sendRequest(ctx context.Context, method, theUrl string, form url.Values) (string, *RateLimit, error) {
+	body, rl, err := c.do(ctx, &apiRequest{
+		method:    method,
+		url:       c.createAPIURL(theUrl),
+		form:      form,
+		retryable: isIdempotentMethod(method),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(body), rl, nil
+}
+
+// do sends the request through the middlewares of the client, waiting for
+// credits, authorizing, refreshing the access token and retrying as needed.
+// It returns the body of a successful response along with its rate limit.
+func (c *ImgurClient) do(ctx context.Context, r *apiRequest) ([]byte, *RateLimit, error) {
+	var res *http.Response
+	refreshed := false
+	for retry := 0; ; {
+		if !r.anonymous {
+			if err := c.waitForCredits(ctx, r.method); err != nil {
+				return nil, nil, wrapErr(-1, "Could not "+strings.ToLower(r.method)+" "+r.url, err)
+			}
+		}
+
+		req, err := c.newRequest(ctx, r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		auth := req.Header.Get("Authorization")
+		start := time.Now()
+		res, err = c.getHandler()(req)
+		if err != nil {
+			reqErr := getRequestErr(ctx, "Could not "+strings.ToLower(r.method)+" "+r.url, err)
+			c.log(ctx, slog.LevelWarn, "imgur request failed", "method", r.method,
+				"path", req.URL.Path, "duration", time.Since(start), "attempt", retry+1, "error", reqErr)
+			if r.retryable && c.retryRequest(ctx, retry, r.method, r.url, nil, reqErr) {
+				retry++
+				continue
+			}
+			return nil, nil, reqErr
+		}
+
+		c.log(ctx, slog.LevelDebug, "imgur request", "method", r.method, "path", req.URL.Path,
+			"status", res.StatusCode, "duration", time.Since(start), "attempt", retry+1)
+
+		if r.anonymous {
+			break
+		}
+		c.trackCredits(res.Header)
+
+		if !refreshed && c.refreshOnUnauthorized(ctx, res, auth, r.canResend()) {
+			refreshed = true
+			continue
+		}
+		if r.retryable && c.retryRequest(ctx, retry, r.method, r.url, res, nil) {
+			retry++
+			continue
+		}
+		break
+	}
+	defer res.Body.Close()
+
+	// Read the whole body
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, getRequestErr(ctx, "Problem reading the body for "+r.url, err)
+	}
+
+	if !(res.StatusCode >= 200 && res.StatusCode <= 300) {
+		return nil, nil, getHTTPErr(res, body, "HTTP status indicates an error for "+r.url)
+	}
+
+	if r.anonymous {
+		return body, nil, nil
+	}
+
+	// Get RateLimit headers
+	rl, err := extractRateLimits(res.Header)
+	if err != nil {
+		c.setLastRateLimitErr(err)
+	} else {
+		c.log(ctx, slog.LevelDebug, "imgur credits", "user_remaining", rl.UserRemaining,
+			"client_remaining", rl.ClientRemaining, "post_remaining", rl.PostRemaining)
+	}
+
+	return body, rl, nil
+}
+
+// newRequest creates the http request of an attempt to send r, with
+// the authorization and RapidAPI headers set.
+func (c *ImgurClient) newRequest(ctx context.Context, r *apiRequest) (*http.Request, error) {
+	var body io.Reader
+	var contentType string
+	if r.form != nil {
+		body = strings.NewReader(r.form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else if r.newBody != nil {
+		rc, bodyType, err := r.newBody()
+		if err != nil {
+			return nil, wrapErr(-1, "Could not create body for "+r.url, err)
+		}
+		body, contentType = rc, bodyType
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+	if err != nil {
+		closeBody(body)
+		return nil, wrapErr(-1, "Could not create request for "+r.url, err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if r.anonymous {
+		return req, nil
+	}
+
+	auth, err := c.getAuthorization(ctx)
+	if err != nil {
+		closeBody(body)
+		return nil, wrapErr(-1, "Could not authorize request for "+r.url, err)
+	}
+
+	req.Header.Set("Authorization", auth)
+	if c.RapidAPIKey != "" {
+		req.Header.Set("X-RapidAPI-Host", rapidAPIHost)
+		req.Header.Set("X-RapidAPI-Key", c.RapidAPIKey)
+	}
+
+	return req, nil
+}
+
+// getHandler returns the handler sending the requests through the
+// middlewares, or the http client only if the client wasn't created
+// by NewImgurClient.
+func (c *ImgurClient) getHandler() Handler {
+	if c.handler == nil {
+		return c.send
+	}
+	return c.handler
+}
+
+// getTokenSource returns the source of the oauth2 token, creating an empty
+// in-memory one if the client wasn't created by NewImgurClient.
+func (c *ImgurClient) getTokenSource() TokenSource {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.tokenSource == nil {
+		c.tokenSource = NewMemoryTokenSource(nil)
+	}
+	return c.tokenSource
+}
+
+// getTokenURL returns the oauth2 token endpoint of the client.
+func (c *ImgurClient) getTokenURL() string {
+	if c.oauthTokenURL == "" {
+		return oauthTokenEndpoint
+	}
+	return c.oauthTokenURL
+}
+
+// log logs the message with the logger of the client, if any.
+func (c *ImgurClient) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Log(ctx, level, msg, args...)
+	}
+}
+
+// send is the innermost handler of the client, which sends the request
+// using its http client.
+func (c *ImgurClient) send(req *http.Request) (*http.Response, error) {
+	if c.HTTPClient == nil {
+		return http.DefaultClient.Do(req)
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// GetImageInfo queries imgur for information on a image
+// returns image info, status code of the request, error
+func (c *ImgurClient) GetImageInfo(id string) (*ImageInfo, error) {
+	return c.GetImageInfoCtx(context.Background(), id)
+}
+
+// GetImageInfoCtx is like GetImageInfo, but uses the given context for its requests.
+func (c *ImgurClient) GetImageInfoCtx(ctx context.Context, id string) (*ImageInfo, error) {
+	body, rl, err := c.getURL(ctx, "image/"+id)
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for image info ID "+id, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var img imageInfoDataWrapper
+	if err := dec.Decode(&img); err != nil {
+		return nil, getDecodeErr("Problem decoding json for imageID "+id, err)
+	}
+	c.setLastRateLimit(rl)
+	c.setLastRateLimitErr(nil)
+
+	if !img.Success {
+		return nil, getErr(img.Status, "Request to imgur failed for imageID "+id+" - "+strconv.Itoa(img.Status))
+	}
+	if img.Info == nil {
+		return nil, getDecodeErr("Imgur returned no data for imageID "+id, nil)
+	}
+
+	img.Info.Limit = rl
+	return img.Info, nil
+}
+
+// DeleteImage deletes an image. For anonymous uploads, idOrDeleteHash has to be
+// the deletehash returned on upload, while authenticated users can also pass
+// the id of an image they own.
+func (c *ImgurClient) DeleteImage(idOrDeleteHash string) error {
+	return c.DeleteImageCtx(context.Background(), idOrDeleteHash)
+}
+
+// DeleteImageCtx is like DeleteImage, but uses the given context for its requests.
+func (c *ImgurClient) DeleteImageCtx(ctx context.Context, idOrDeleteHash string) error {
+	if idOrDeleteHash == "" {
+		return getValidationErr("Invalid image id or deletehash")
+	}
+
+	body, rl, err := c.deleteURL(ctx, "image/"+idOrDeleteHash)
+	if err != nil {
+		return wrapErr(-1, "Problem deleting image "+idOrDeleteHash, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, "deleting image "+idOrDeleteHash)
+}
+
+// UpdateImageInfo updates the title and description of an image. Empty values
+// are left unchanged. For anonymous uploads, idOrDeleteHash has to be the deletehash.
+func (c *ImgurClient) UpdateImageInfo(idOrDeleteHash, title, description string) error {
+	return c.UpdateImageInfoCtx(context.Background(), idOrDeleteHash, title, description)
+}
+
+// UpdateImageInfoCtx is like UpdateImageInfo, but uses the given context for its requests.
+func (c *ImgurClient) UpdateImageInfoCtx(ctx context.Context, idOrDeleteHash, title, description string) error {
+	if idOrDeleteHash == "" {
+		return getValidationErr("Invalid image id or deletehash")
+	}
+
+	form := url.Values{}
+	if title != "" {
+		form.Add("title", title)
+	}
+	if description != "" {
+		form.Add("description", description)
+	}
+
+	body, rl, err := c.postURL(ctx, "image/"+idOrDeleteHash, form)
+	if err != nil {
+		return wrapErr(-1, "Problem updating image "+idOrDeleteHash, err)
+	}
+
+	return c.decodeBasicResponse(body, rl, "updating image "+idOrDeleteHash)
+}
+
+// FavoriteImage toggles the favorite state of an image for the current user.
+// It returns true if the image is now favorited, false if it has been unfavorited.
+// Requires the client to be authenticated.
+func (c *ImgurClient) FavoriteImage(id string) (bool, error) {
+	return c.FavoriteImageCtx(context.Background(), id)
+}
+
+// FavoriteImageCtx is like FavoriteImage, but uses the given context for its requests.
+func (c *ImgurClient) FavoriteImageCtx(ctx context.Context, id string) (bool, error) {
+	return c.toggleFavorite(ctx, "image/"+id+"/favorite", "image "+id)
+}
+
+func (c *ImgurClient) toggleFavorite(ctx context.Context, theUrl, what string) (bool, error) {
+	if !c.IsAuthenticated() {
+		return false, getAuthErr("Favoriting " + what + " requires an authenticated client")
+	}
+
+	body, rl, err := c.postURL(ctx, theUrl, url.Values{})
+	if err != nil {
+		return false, wrapErr(-1, "Problem favoriting "+what, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+	var fav stringDataWrapper
+	if err := dec.Decode(&fav); err != nil {
+		return false, getDecodeErr("Problem decoding json for favoriting "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !fav.Success {
+		return false, getErr(fav.Status, "Request to imgur failed for favoriting "+what+" - "+strconv.Itoa(fav.Status))
+	}
+
+	return fav.Data == "favorited", nil
+}
+
+// decodeBasicResponse decodes a response whose data is only a boolean
+// indicating whether the operation has succeeded.
+func (c *ImgurClient) decodeBasicResponse(body string, rl *RateLimit, what string) error {
+	dec := json.NewDecoder(strings.NewReader(body))
+	var basic basicDataWrapper
+	if err := dec.Decode(&basic); err != nil {
+		return getDecodeErr("Problem decoding json for "+what, err)
+	}
+	c.setLastRateLimit(rl)
+
+	if !basic.Success {
+		return getErr(basic.Status, "Request to imgur failed for "+what+" - "+strconv.Itoa(basic.Status))
+	}
+
+	return nil
+}
+
+// GetRateLimit returns the current rate limit without doing anything else
+func (c *ImgurClient) GetRateLimit() (*RateLimit, error) {
+	return c.GetRateLimitCtx(context.Background())
+}
+
+// GetRateLimitCtx is like GetRateLimit, but uses the given context for its requests.
+func (c *ImgurClient) GetRateLimitCtx(ctx context.Context) (*RateLimit, error) {
+	// We are requesting any URL and parse the returned HTTP headers
+	body, rl, err := c.getURL(ctx, "account/kaffeeshare")
+
+	if err != nil {
+		return nil, wrapErr(-1, "Problem getting URL for rate", err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(body))
+
+	var bodyDecoded rateLimitDataWrapper
+	if err := dec.Decode(&bodyDecoded); err != nil {
+		decodeErr := getDecodeErr("Problem decoding json for ratelimit", err)
+		c.setLastRateLimitErr(decodeErr)
+		return nil, decodeErr
+	}
+
+	if !bodyDecoded.Success {
+		requestErr := getErr(bodyDecoded.Status, "Request to imgur failed for ratelimit - "+strconv.Itoa(bodyDecoded.Status))
+		c.setLastRateLimitErr(requestErr)
+		return nil, requestErr
+	}
+
+	var ret RateLimit
+	ret.ClientLimit = rl.ClientLimit
+	ret.ClientRemaining = rl.ClientRemaining
+	ret.UserLimit = rl.UserLimit
+	ret.UserRemaining = rl.UserRemaining
+	ret.UserReset = rl.UserReset
+	c.setLastRateLimit(&ret)
+	c.setLastRateLimitErr(nil)
+
+	return &ret, nil
+}
+
+// UploadImage uploads the image to imgur
+// image                Can be a binary file, base64 data, or a URL for an image. (up to 10MB)
+// album       optional The id of the album you want to add the image to.
+//
+//	For anonymous albums, album should be the deleteHash that is returned at creation.
+//
+// dType                The type of the file that's being sent; file, base64 or URL
+// title       optional The title of the image.
+// description optional The description of the image.
+// returns image info, status code of the upload, error
+func (c *ImgurClient) UploadImage(image []byte, album, dType, title, description string) (*ImageInfo, error) {
+	return c.UploadImageCtx(context.Background(), image, album, dType, title, description)
+}
+
+// UploadImageCtx is like UploadImage, but uses the given context for its requests.
+func (c *ImgurClient) UploadImageCtx(ctx context.Context, image []byte, album, dType, title, description string) (*ImageInfo, error) {
+	return c.UploadImageWithOptionsCtx(ctx, image, dType, &UploadOptions{
+		Album:       album,
+		Title:       title,
+		Description: description,
+	})
+}
+
+// UploadImageWithOptions is like UploadImage, but takes the optional
+// parameters of the upload as UploadOptions. opts may be nil.
+func (c *ImgurClient) UploadImageWithOptions(image []byte, dType string, opts *UploadOptions) (*ImageInfo, error) {
+	return c.UploadImageWithOptionsCtx(context.Background(), image, dType, opts)
+}
+
+// UploadImageWithOptionsCtx is like UploadImageWithOptions, but uses the given context for its requests.
+func (c *ImgurClient) UploadImageWithOptionsCtx(ctx context.Context, image []byte, dType string, opts *UploadOptions) (*ImageInfo, error) {
+	if image == nil {
+		return nil, getValidationErr("Invalid image")
+	}
+	if dType != "file" && dType != "base64" && dType != "URL" {
+		return nil, getValidationErr("Passed invalid dType: " + dType + ". Please use file/base64/URL.")
+	}
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	if dType == "file" {
+		// binary files are streamed as multipart, instead of
+		// being copied into an url-encoded form.
+		return c.uploadMultipart(ctx, "image", "image", bytes.NewReader(image), opts)
+	}
+
+	form := createUploadForm(image, opts.Album, dType, opts.Title, opts.Description)
+	encodedForm := form.Encode()
+
+	return c.postUpload(ctx, "image", true, func() (io.ReadCloser, string, error) {
+		body := newProgressReader(strings.NewReader(encodedForm), int64(len(encodedForm)), opts)
+		return io.NopCloser(body), "application/x-www-form-urlencoded", nil
+	})
+}
+
+// UploadImageFromFile uploads a file given by the filename string to imgur.
+func (c *ImgurClient) UploadImageFromFile(filename, album, title, description string) (*ImageInfo, error) {
+	return c.UploadImageFromFileCtx(context.Background(), filename, album, title, description)
+}
+
+// UploadImageFromFileCtx is like UploadImageFromFile, but uses the given context for its requests.
+func (c *ImgurClient) UploadImageFromFileCtx(ctx context.Context, filename, album, title, description string) (*ImageInfo, error) {
+	return c.UploadImageFromFileWithOptionsCtx(ctx, filename, &UploadOptions{
+		Album:       album,
+		Title:       title,
+		Description: description,
+	})
+}
+
+// UploadImageFromFileWithOptions is like UploadImageFromFile, but takes the
+// optional parameters of the upload as UploadOptions. opts may be nil.
+// If opts.Name is empty, the base name of the file is used.
+func (c *ImgurClient) UploadImageFromFileWithOptions(filename string, opts *UploadOptions) (*ImageInfo, error) {
+	return c.UploadImageFromFileWithOptionsCtx(context.Background(), filename, opts)
+}
+
+// UploadImageFromFileWithOptionsCtx is like UploadImageFromFileWithOptions, but uses the given context for its requests.
+func (c *ImgurClient) UploadImageFromFileWithOptionsCtx(ctx context.Context, filename string, opts *UploadOptions) (*ImageInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, newErr(ErrorKindValidation, "Could not open file "+filename, err)
+	}
+	defer f.Close()
+
+	fileOpts := UploadOptions{}
+	if opts != nil {
+		fileOpts = *opts
+	}
+	if fileOpts.Name == "" {
+		fileOpts.Name = filepath.Base(filename)
+	}
+
+	return c.uploadMultipart(ctx, "image", "image", f, &fileOpts)
+}
+
+// UploadImageFromReader uploads the image read from r to imgur.
+// The body is streamed as multipart/form-data, so the image is never
+// buffered in memory as a whole. opts may be nil.
+func (c *ImgurClient) UploadImageFromReader(r io.Reader, opts *UploadOptions) (*ImageInfo, error) {
+	return c.UploadImageFromReaderCtx(context.Background(), r, opts)
+}
+
+// UploadImageFromReaderCtx is like UploadImageFromReader, but uses the given context for its requests.
+func (c *ImgurClient) UploadImageFromReaderCtx(ctx context.Context, r io.Reader, opts *UploadOptions) (*ImageInfo, error) {
+	if r == nil {
+		return nil, getValidationErr("Invalid image reader")
+	}
+
+	return c.uploadMultipart(ctx, "image", "image", r, opts)
+}
+
+// UploadVideo uploads the video read from r to imgur and waits until it has
+// been processed, so the returned info contains the mp4 and gifv links.
+// opts.Name is required, as imgur determines the format of the video from it;
+// mp4, webm and mov videos are accepted.
+func (c *ImgurClient) UploadVideo(r io.Reader, opts *UploadOptions) (*ImageInfo, error) {
+	return c.UploadVideoCtx(context.Background(), r, opts)
+}
+
+// UploadVideoCtx is like UploadVideo, but uses the given context for its requests.
+func (c *ImgurClient) UploadVideoCtx(ctx context.Context, r io.Reader, opts *UploadOptions) (*ImageInfo, error) {
+	if r == nil {
+		return nil, getValidationErr("Invalid video reader")
+	}
+	if opts == nil || !isVideoFile(opts.Name) {
+		return nil, getValidationErr("Passed invalid video name. Please use a mp4, webm or mov file.")
+	}
+
+	info, err := c.uploadMultipart(ctx, "upload", "video", r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.waitForProcessing(ctx, info, opts.PollInterval, opts.ProcessingTimeout)
+}
+
+// UploadVideoFromFile uploads the video file given by the filename string to
+// imgur and waits until it has been processed. opts may be nil.
+func (c *ImgurClient) UploadVideoFromFile(filename string, opts *UploadOptions) (*ImageInfo, error) {
+	return c.UploadVideoFromFileCtx(context.Background(), filename, opts)
+}
+
+// UploadVideoFromFileCtx is like UploadVideoFromFile, but uses the given context for its requests.
+func (c *ImgurClient) UploadVideoFromFileCtx(ctx context.Context, filename string, opts *UploadOptions) (*ImageInfo, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, newErr(ErrorKindValidation, "Could not open file "+filename, err)
+	}
+	defer f.Close()
+
+	fileOpts := UploadOptions{}
+	if opts != nil {
+		fileOpts = *opts
+	}
+	if fileOpts.Name == "" {
+		fileOpts.Name = filepath.Base(filename)
+	}
+
+	return c.UploadVideoCtx(ctx, f, &fileOpts)
+}
+
+// WaitForProcessing polls imgur until the uploaded video with the given id
+// has been processed, or the timeout is reached. Zero values for interval
+// and timeout use DefaultVideoPollInterval and DefaultVideoProcessingTimeout.
+func (c *ImgurClient) WaitForProcessing(id string, interval, timeout time.Duration) (*ImageInfo, error) {
+	return c.WaitForProcessingCtx(context.Background(), id, interval, timeout)
+}
+
+// WaitForProcessingCtx is like WaitForProcessing, but uses the given context for its requests.
+func (c *ImgurClient) WaitForProcessingCtx(ctx context.Context, id string, interval, timeout time.Duration) (*ImageInfo, error) {
+	info, err := c.GetImageInfoCtx(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.waitForProcessing(ctx, info, interval, timeout)
+}
+
+func (c *ImgurClient) waitForProcessing(ctx context.Context, info *ImageInfo, interval, timeout time.Duration) (*ImageInfo, error) {
+	if interval <= 0 {
+		interval = DefaultVideoPollInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultVideoProcessingTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	current := info
+	for !current.isProcessed() {
+		if current.Processing != nil && current.Processing.Status == ProcessingStatusFailed {
+			return nil, newErr(ErrorKindServerError, "Imgur failed to process video "+info.ID, nil)
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			// imgur taking too long isn't a cancellation of the caller's context.
+			return nil, newErr(ErrorKindServerError, "Timed out waiting for video "+info.ID+" to be processed", nil)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, newErr(ErrorKindCanceled, "Waiting for video "+info.ID+" to be processed - request canceled", ctx.Err())
+		case <-timer.C:
+		}
+
+		next, err := c.GetImageInfoCtx(ctx, info.ID)
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+
+	// the deletehash is only returned to the uploader, keep it around.
+	if current.DeleteHash == "" {
+		current.DeleteHash = info.DeleteHash
+	}
+
+	return current, nil
+}
+
+// uploadMultipart streams r as the given form field to the endpoint.
+// If r is an io.Seeker, it's rewound whenever the request has to be sent again.
+func (c *ImgurClient) uploadMultipart(ctx context.Context, endpoint, field string, r io.Reader, opts *UploadOptions) (*ImageInfo, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+
+	total := opts.Size
+	if total <= 0 {
+		total = getReaderSize(r)
+	}
+
+	seeker, _ := r.(io.Seeker)
+	var offset int64
+	if seeker != nil {
+		var err error
+		offset, err = seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			seeker = nil
+		}
+	}
+
+	var body io.ReadCloser
+	var done <-chan struct{}
+	return c.postUpload(ctx, endpoint, seeker != nil, func() (io.ReadCloser, string, error) {
+		if body != nil {
+			if seeker == nil {
+				return nil, "", errors.New("the upload body can not be sent again")
+			}
+
+			// the previous attempt may have been answered before its body
+			// has been sent entirely, so stop it from reading r first.
+			body.Close()
+			<-done
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, "", err
+			}
+		}
+
+		var contentType string
+		body, contentType, done = createMultipartBody(field, newProgressReader(r, total, opts), opts)
+		return body, contentType, nil
+	})
+}
+
+// postUpload posts an upload body to the endpoint and decodes the resulting image.
+// newBody is called for every attempt and returns the body along with its content type.
+// Failed uploads are only retried if replayable is true.
+func (c *ImgurClient) postUpload(ctx context.Context, endpoint string, replayable bool, newBody func() (io.ReadCloser, string, error)) (*ImageInfo, error) {
+	body, rl, err := c.do(ctx, &apiRequest{
+		method:    "POST",
+		url:       c.createUploadURL(endpoint),
+		newBody:   newBody,
+		retryable: replayable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var img imageInfoDataWrapper
+	if err = dec.Decode(&img); err != nil {
+		return nil, getDecodeErr("Problem decoding json result from image upload. JSON(?): "+string(body), err)
+	}
+
+	if !img.Success {
+		return nil, getErr(img.Status, "Upload to imgur failed with status: "+strconv.Itoa(img.Status))
+	}
+	if img.Info == nil {
+		return nil, getDecodeErr("Imgur returned no data for the uploaded image", nil)
+	}
+
+	img.Info.Limit = rl
+	c.setLastRateLimit(img.Info.Limit)
+
+	return img.Info, nil
+}
+
+// GetAuthorizeURL returns the url the user has to visit in order to
+// authorize the application. state is optional and is passed back to the
+// redirect url unchanged.
+func (c *ImgurClient) GetAuthorizeURL(responseType OAuthResponseType, state string) string {
+	values := url.Values{}
+	values.Add("client_id", c.ImgurClientID)
+	values.Add("response_type", string(responseType))
+	if state != "" {
+		values.Add("state", state)
+	}
+
+	authorizeURL := c.oauthAuthorizeURL
+	if authorizeURL == "" {
+		authorizeURL = oauthAuthorizeEndpoint
+	}
+	return authorizeURL + "?" + values.Encode()
+}
+
+// ExchangeCode exchanges the authorization code received on the redirect url
+// for an access token. The token is stored on the client and used for
+// signing all further requests.
+func (c *ImgurClient) ExchangeCode(code string) (*OAuthToken, error) {
+	return c.ExchangeCodeCtx(context.Background(), code)
+}
+
+// ExchangeCodeCtx is like ExchangeCode, but uses the given context for its requests.
+func (c *ImgurClient) ExchangeCodeCtx(ctx context.Context, code string) (*OAuthToken, error) {
+	if code == "" {
+		return nil, getValidationErr("Invalid authorization code")
+	}
+
+	form := createTokenForm(c.ImgurClientID, c.ClientSecret, grantTypeAuthorizationCode)
+	form.Add("code", code)
+
+	return c.requestToken(ctx, form)
+}
+
+// ExchangePin exchanges the pin the user has been shown by imgur for an
+// access token. The token is stored on the client and used for
+// signing all further requests.
+func (c *ImgurClient) ExchangePin(pin string) (*OAuthToken, error) {
+	return c.ExchangePinCtx(context.Background(), pin)
+}
+
+// ExchangePinCtx is like ExchangePin, but uses the given context for its requests.
+func (c *ImgurClient) ExchangePinCtx(ctx context.Context, pin string) (*OAuthToken, error) {
+	if pin == "" {
+		return nil, getValidationErr("Invalid pin")
+	}
+
+	form := createTokenForm(c.ImgurClientID, c.ClientSecret, grantTypePin)
+	form.Add("pin", pin)
+
+	return c.requestToken(ctx, form)
+}
+
+// RefreshAccessToken requests a new access token using the refresh token
+// of the current token. The new token replaces the current one in the
+// token source.
+func (c *ImgurClient) RefreshAccessToken() (*OAuthToken, error) {
+	return c.RefreshAccessTokenCtx(context.Background())
+}
+
+// RefreshAccessTokenCtx is like RefreshAccessToken, but uses the given context for its requests.
+func (c *ImgurClient) RefreshAccessTokenCtx(ctx context.Context) (*OAuthToken, error) {
+	return c.refreshToken(ctx, "")
+}
+
+// refreshToken refreshes the access token, unless it has already been
+// refreshed by another request since the stale access token was used.
+// Refreshes are serialized, so a refresh token is only redeemed once.
+func (c *ImgurClient) refreshToken(ctx context.Context, stale string) (*OAuthToken, error) {
+	token, rotated, err := c.rotateToken(ctx, stale)
+	if err != nil {
+		return nil, err
+	}
+
+	// the callback is called after releasing refreshMut, so it
+	// can use the client without deadlocking.
+	if rotated && c.onTokenRotate != nil {
+		c.onTokenRotate(token)
+	}
+
+	return token, nil
+}
+
+// rotateToken refreshes the access token while holding refreshMut, see
+// refreshToken. It returns true if a new token has been obtained.
+func (c *ImgurClient) rotateToken(ctx context.Context, stale string) (*OAuthToken, bool, error) {
+	c.refreshMut.Lock()
+	defer c.refreshMut.Unlock()
+
+	current, err := c.getTokenSource().Token()
+	if err != nil {
+		return nil, false, wrapErr(-1, "Could not get the current token", err)
+	}
+
+	if stale != "" && current != nil && current.AccessToken != "" && current.AccessToken != stale {
+		return current, false, nil
+	}
+
+	if current == nil || current.RefreshToken == "" {
+		return nil, false, getAuthErr("No refresh token available")
+	}
+
+	form := createTokenForm(c.ImgurClientID, c.ClientSecret, grantTypeRefreshToken)
+	form.Add("refresh_token", current.RefreshToken)
+
+	token, err := c.requestToken(ctx, form)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.log(ctx, slog.LevelInfo, "imgur access token refreshed", "expiry", token.Expiry)
+	return token, true, nil
+}
+
+// SetToken sets the oauth2 token used for signing requests.
+// Passing nil switches the client back to anonymous (client-id) mode.
+func (c *ImgurClient) SetToken(token *OAuthToken) error {
+	return c.getTokenSource().SetToken(token)
+}
+
+// GetToken returns the oauth2 token currently used by the client, if any.
+func (c *ImgurClient) GetToken() *OAuthToken {
+	token, _ := c.getTokenSource().Token()
+	return token
+}
+
+// IsAuthenticated returns true if the client is acting on behalf of a user.
+func (c *ImgurClient) IsAuthenticated() bool {
+	token := c.GetToken()
+	return token != nil && token.AccessToken != ""
+}
+
+// getAuthorization returns the value of the Authorization header,
+// refreshing the access token first if it's about to expire.
+func (c *ImgurClient) getAuthorization(ctx context.Context) (string, error) {
+	token, err := c.getTokenSource().Token()
+	if err != nil {
+		return "", err
+	}
+
+	if token == nil || token.AccessToken == "" {
+		return "Client-ID " + c.ImgurClientID, nil
+	}
+
+	if token.RefreshToken != "" && token.expiresWithin(tokenExpiryDelta) {
+		token, err = c.refreshToken(ctx, token.AccessToken)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "Bearer " + token.AccessToken, nil
+}
+
+// refreshOnUnauthorized refreshes the access token if the response was
+// rejected because of it. It returns true if the request should be sent again,
+// in which case the body of the response is already closed. If resend is false,
+// the token is refreshed for the next requests only, and false is returned.
+func (c *ImgurClient) refreshOnUnauthorized(ctx context.Context, res *http.Response, auth string, resend bool) bool {
+	if res.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	token := c.GetToken()
+	if token == nil || token.RefreshToken == "" {
+		return false
+	}
+
+	if _, err := c.refreshToken(ctx, strings.TrimPrefix(auth, "Bearer ")); err != nil || !resend {
+		return false
+	}
+
+	res.Body.Close()
+	return true
+}
+
+// trackCredits updates the credits known to the governor from the
+// rate limit headers of a response. Headers missing from the response
+// (e.g. the POST limits of a GET request) leave the known credits untouched.
+func (c *ImgurClient) trackCredits(h http.Header) {
+	if c.rateLimitPolicy == nil {
+		return
+	}
+
+	rl, _ := extractRateLimits(h)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if h.Get("X-RateLimit-UserRemaining") != "" {
+		c.credits.UserLimit = rl.UserLimit
+		c.credits.UserRemaining = rl.UserRemaining
+		c.credits.UserReset = rl.UserReset
+	}
+	if h.Get("X-RateLimit-ClientRemaining") != "" {
+		c.credits.ClientLimit = rl.ClientLimit
+		c.credits.ClientRemaining = rl.ClientRemaining
+	}
+	if h.Get("X-Post-Rate-Limit-Remaining") != "" {
+		c.credits.PostLimit = rl.PostLimit
+		c.credits.PostRemaining = rl.PostRemaining
+		c.credits.PostReset = rl.PostReset
+	}
+}
+
+// waitForCredits holds a request with the given method back until the
+// credits it needs are reset, according to the rate limit policy.
+func (c *ImgurClient) waitForCredits(ctx context.Context, method string) error {
+	policy := c.rateLimitPolicy
+	if policy == nil {
+		return nil
+	}
+
+	c.mut.Lock()
+	credits := c.credits
+	c.mut.Unlock()
+
+	if credits.ClientLimit > 0 && credits.ClientRemaining <= policy.MinClientRemaining {
+		return newErr(ErrorKindRateLimited, "Application credits are exhausted - "+
+			strconv.FormatInt(credits.ClientRemaining, 10)+" remaining", nil)
+	}
+
+	var reset time.Time
+	now := time.Now()
+	if credits.UserLimit > 0 && credits.UserRemaining <= policy.MinUserRemaining &&
+		credits.UserReset.After(now) {
+		reset = credits.UserReset
+	}
+	if method == "POST" && credits.PostLimit > 0 && credits.PostRemaining <= policy.MinPostRemaining &&
+		credits.PostReset.After(reset) {
+		reset = credits.PostReset
+	}
+	if !reset.After(now) {
+		return nil
+	}
+
+	wait := reset.Sub(now)
+	if policy.FailFast || (policy.MaxWait > 0 && wait > policy.MaxWait) {
+		return newErr(ErrorKindRateLimited, "Credits are exhausted until "+reset.Format(time.RFC3339), nil)
+	}
+
+	c.log(ctx, slog.LevelInfo, "waiting for imgur credits", "method", method, "wait", wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return newErr(ErrorKindCanceled, "Waiting for credits to be reset - request canceled", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryRequest decides whether a failed attempt is retried according to the
+// retry policy of the client. If so, it discards the response and waits
+// before returning true; a canceled context cuts the wait short, making
+// the next attempt fail right away.
+func (c *ImgurClient) retryRequest(ctx context.Context, retry int, method, theUrl string, res *http.Response, err error) bool {
+	policy := c.retryPolicy
+	if policy == nil || retry+1 >= policy.getMaxAttempts() || ctx.Err() != nil {
+		return false
+	}
+	if !policy.isRetryable(res, err) {
+		return false
+	}
+
+	info := &RetryInfo{
+		Attempt: retry + 1,
+		Method:  method,
+		URL:     theUrl,
+		Err:     err,
+		Delay:   policy.getDelay(retry, res),
+	}
+	if res != nil {
+		info.Status = res.StatusCode
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	c.log(ctx, slog.LevelInfo, "retrying imgur request", "method", method, "attempt", info.Attempt,
+		"status", info.Status, "delay", info.Delay, "error", err)
+	if policy.OnRetry != nil {
+		policy.OnRetry(info)
+	}
+
+	timer := time.NewTimer(info.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+
+	return true
+}
+
+func (c *ImgurClient) requestToken(ctx context.Context, form url.Values) (*OAuthToken, error) {
+	body, _, err := c.do(ctx, &apiRequest{
+		method:    "POST",
+		url:       c.getTokenURL(),
+		form:      form,
+		anonymous: true,
+	})
+	if err != nil {
+		return nil, wrapErr(-1, "Token request failed", err)
+	}
+
+	token := new(OAuthToken)
+	if err = json.Unmarshal(body, token); err != nil {
+		return nil, getDecodeErr("Problem decoding json for token", err)
+	}
+
+	if token.AccessToken == "" {
+		return nil, getDecodeErr("Token request returned no access token", nil)
+	}
+
+	token.setExpiry()
+	if token.RefreshToken == "" && form.Get("refresh_token") != "" {
+		token.RefreshToken = form.Get("refresh_token")
+	}
+
+	if err = c.getTokenSource().SetToken(token); err != nil {
+		return nil, wrapErr(-1, "Could not store the new token", err)
+	}
+
+	return token, nil
+}
+
+// --------------------------------------------------------
+
+// canResend returns true if the request can be sent again, i.e. it has no
+// body, an url-encoded form or a body which can be replayed.
+func (r *apiRequest) canResend() bool {
+	return r.newBody == nil || r.retryable
+}
+
+// --------------------------------------------------------
+
+// IsExpired returns true if the access token has passed its expiry time.
+// Tokens without a known expiry are never considered expired.
+func (t *OAuthToken) IsExpired() bool {
+	return !t.Expiry.IsZero() && time.Now().After(t.Expiry)
+}
+
+// LogValue implements slog.LogValuer, so logging a token never
+// writes its access or refresh token.
+func (t *OAuthToken) LogValue() slog.Value {
+	if t == nil {
+		return slog.Value{}
+	}
+
+	return slog.GroupValue(
+		slog.String("access_token", redactedValue),
+		slog.String("refresh_token", redactedValue),
+		slog.String("account_username", t.AccountUsername),
+		slog.Time("expiry", t.Expiry),
+	)
+}
+
+func (t *OAuthToken) expiresWithin(d time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Now().Add(d).After(t.Expiry)
+}
+
+func (t *OAuthToken) setExpiry() {
+	if t.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+// --------------------------------------------------------
+
+// Token returns the token held in memory.
+func (s *MemoryTokenSource) Token() (*OAuthToken, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.token, nil
+}
+
+// SetToken replaces the token held in memory.
+func (s *MemoryTokenSource) SetToken(token *OAuthToken) error {
+	s.mut.Lock()
+	s.token = token
+	s.mut.Unlock()
+
+	return nil
+}
+
+// --------------------------------------------------------
+
+// Token returns the token loaded from (or last written to) the file.
+func (s *FileTokenSource) Token() (*OAuthToken, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.token, nil
+}
+
+// Path returns the path of the file the token is persisted to.
+func (s *FileTokenSource) Path() string {
+	return s.path
+}
+
+// SetToken replaces the token and writes it to the file.
+// Passing nil removes the file.
+func (s *FileTokenSource) SetToken(token *OAuthToken) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if token == nil {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.token = nil
+		return nil
+	}
+
+	b, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so a crash never leaves
+	// a half-written token behind.
+	tmpPath := s.path + ".tmp"
+	if err = os.WriteFile(tmpPath, b, 0600); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+
+	s.token = token
+	return nil
+}
+
+// --------------------------------------------------------
+
+// Next advances the pager to the next item, fetching the next page if needed.
+// It returns false once there are no more items, the item limit has been
+// reached, ctx is done or fetching a page has failed; check Err afterwards.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	if p.maxItems > 0 && p.count >= p.maxItems {
+		p.done = true
+		return false
+	}
+
+	if err := ctx.Err(); err != nil {
+		p.err = newErr(ErrorKindCanceled, "Fetching page "+strconv.Itoa(p.page)+" - request canceled", err)
+		return false
+	}
+
+	for p.index >= len(p.items) {
+		items, rl, err := p.fetch(ctx, p.page)
+		if err != nil {
+			p.err = err
+			return false
+		}
+
+		if rl != nil {
+			p.limit = rl
+		}
+
+		if len(items) == 0 {
+			p.done = true
+			return false
+		}
+
+		p.page++
+		p.items = items
+		p.index = 0
+	}
+
+	p.current = p.items[p.index]
+	p.index++
+	p.count++
+
+	return true
+}
+
+// Item returns the current item. It's only valid after Next returned true.
+func (p *Pager[T]) Item() T {
+	return p.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// RateLimit returns the rate limit of the most recently fetched page.
+func (p *Pager[T]) RateLimit() *RateLimit {
+	return p.limit
+}
+
+// Page returns the number of pages fetched so far.
+func (p *Pager[T]) Page() int {
+	return p.page
+}
+
+// All iterates over the remaining items and returns them.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for p.Next(ctx) {
+		all = append(all, p.Item())
+	}
+
+	return all, p.Err()
+}
+
+// --------------------------------------------------------
+
+// All restricts the results to items containing all of the words.
+func (q *SearchQuery) All(words ...string) *SearchQuery {
+	q.all = append(q.all, words...)
+	return q
+}
+
+// Any restricts the results to items containing any of the words.
+func (q *SearchQuery) Any(words ...string) *SearchQuery {
+	q.any = append(q.any, words...)
+	return q
+}
+
+// Exactly restricts the results to items containing the exact phrase.
+func (q *SearchQuery) Exactly(phrase string) *SearchQuery {
+	q.exactly = phrase
+	return q
+}
+
+// Not excludes items containing any of the words from the results.
+func (q *SearchQuery) Not(words ...string) *SearchQuery {
+	q.not = append(q.not, words...)
+	return q
+}
+
+// Type restricts the results to the given file type.
+func (q *SearchQuery) Type(t SearchType) *SearchQuery {
+	q.qType = t
+	return q
+}
+
+// Size restricts the results to the given image size.
+func (q *SearchQuery) Size(size SearchSize) *SearchQuery {
+	q.size = size
+	return q
+}
+
+// Sort sets the order of the results. Only GallerySortTime, GallerySortViral
+// and GallerySortTop are supported; defaults to GallerySortTime.
+func (q *SearchQuery) Sort(sort GallerySort) *SearchQuery {
+	q.sort = sort
+	return q
+}
+
+// Window sets the date range of the results, only used with GallerySortTop.
+func (q *SearchQuery) Window(window GalleryWindow) *SearchQuery {
+	q.window = window
+	return q
+}
+
+// Page sets the page of the results, starting from 0.
+func (q *SearchQuery) Page(page int) *SearchQuery {
+	q.page = page
+	return q
+}
+
+// IsEmpty returns true if neither a query string nor any of the
+// advanced word parameters are set.
+func (q *SearchQuery) IsEmpty() bool {
+	return q.query == "" && len(q.all) == 0 && len(q.any) == 0 &&
+		q.exactly == "" && len(q.not) == 0
+}
+
+// Values returns the query parameters of the search.
+func (q *SearchQuery) Values() url.Values {
+	values := url.Values{}
+
+	if q.query != "" {
+		values.Add("q", q.query)
+	}
+	if len(q.all) != 0 {
+		values.Add("q_all", strings.Join(q.all, " "))
+	}
+	if len(q.any) != 0 {
+		values.Add("q_any", strings.Join(q.any, " "))
+	}
+	if q.exactly != "" {
+		values.Add("q_exactly", q.exactly)
+	}
+	if len(q.not) != 0 {
+		values.Add("q_not", strings.Join(q.not, " "))
+	}
+	if q.qType != "" {
+		values.Add("q_type", string(q.qType))
+	}
+	if q.size != "" {
+		values.Add("q_size_px", string(q.size))
+	}
+
+	return values
+}
+
+// String returns the encoded query parameters of the search.
+func (q *SearchQuery) String() string {
+	return q.Values().Encode()
+}
+
+func (q *SearchQuery) createURL(page int) string {
+	sort := q.sort
+	if sort == "" {
+		sort = GallerySortTime
+	}
+
+	window := q.window
+	if window == "" {
+		window = GalleryWindowAll
+	}
+
+	return "gallery/search/" + string(sort) + "/" + string(window) + "/" + strconv.Itoa(page) + "?" + q.String()
+}
+
+// --------------------------------------------------------
+
+// Walk calls fn for the comment and all of its replies in depth-first order,
+// with depth being 0 for the comment itself. If fn returns false, the replies
+// of that comment are skipped.
+func (c *Comment) Walk(fn func(comment *Comment, depth int) bool) {
+	c.walk(fn, 0)
+}
+
+func (c *Comment) walk(fn func(comment *Comment, depth int) bool, depth int) {
+	if !fn(c, depth) {
+		return
+	}
+
+	for i := range c.Children {
+		c.Children[i].walk(fn, depth+1)
+	}
+}
+
+// Flatten returns the comment along with all of its replies as a flat list,
+// in depth-first order. The Children of the returned comments are kept as is.
+func (c *Comment) Flatten() []Comment {
+	var flat []Comment
+	c.Walk(func(comment *Comment, depth int) bool {
+		flat = append(flat, *comment)
+		return true
+	})
+
+	return flat
+}
+
+// CountReplies returns the number of replies to the comment, at any depth.
+func (c *Comment) CountReplies() int {
+	count := 0
+	for i := range c.Children {
+		count += 1 + c.Children[i].CountReplies()
+	}
+
+	return count
+}
+
+// --------------------------------------------------------
+
+// IsAlbum returns true if the item is a gallery album.
+func (i *GalleryItem) IsAlbum() bool {
+	return i.Album != nil
+}
+
+// GetID returns the id of the image or album.
+func (i *GalleryItem) GetID() string {
+	if i.Album != nil {
+		return i.Album.ID
+	}
+	if i.Image != nil {
+		return i.Image.ID
+	}
+	return ""
+}
+
+// ToGenericInfo returns the item as a GenericInfo.
+func (i *GalleryItem) ToGenericInfo() *GenericInfo {
+	info := &GenericInfo{
+		GImage: i.Image,
+		GAlbum: i.Album,
+	}
+
+	if i.Album != nil {
+		info.Limit = i.Album.Limit
+	} else if i.Image != nil {
+		info.Limit = i.Image.Limit
+	}
+
+	return info
+}
+
+// UnmarshalJSON decodes the item into either an image or an album,
+// depending on its is_album field.
+func (i *GalleryItem) UnmarshalJSON(data []byte) error {
+	var kind struct {
+		IsAlbum bool `json:"is_album"`
+	}
+	if err := json.Unmarshal(data, &kind); err != nil {
+		return err
+	}
+
+	i.Image = nil
+	i.Album = nil
+	if kind.IsAlbum {
+		i.Album = new(GalleryAlbumInfo)
+		return json.Unmarshal(data, i.Album)
+	}
+
+	i.Image = new(GalleryImageInfo)
+	return json.Unmarshal(data, i.Image)
+}
+
+// MarshalJSON encodes the image or album of the item.
+func (i GalleryItem) MarshalJSON() ([]byte, error) {
+	if i.Album != nil {
+		return json.Marshal(i.Album)
+	}
+	return json.Marshal(i.Image)
+}
+
+func (i *GalleryItem) setLimit(rl *RateLimit) {
+	if i.Album != nil {
+		i.Album.Limit = rl
+	}
+	if i.Image != nil {
+		i.Image.Limit = rl
+	}
+}
+
+// --------------------------------------------------------
+
+// isProcessed returns true if the links of an uploaded video are ready.
+func (i *ImageInfo) isProcessed() bool {
+	if i.Processing != nil && i.Processing.Status != ProcessingStatusCompleted {
+		return false
+	}
+	return i.Mp4 != ""
+}
+
+// --------------------------------------------------------
+
+// Percent returns the progress of the upload in percent,
+// or -1 if the total size is unknown.
+func (p UploadProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.BytesSent) * 100 / float64(p.Total)
+}
+
+// --------------------------------------------------------
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+
+	now := time.Now()
+	if err == io.EOF {
+		r.report(now, true)
+	} else if now.Sub(r.lastReport) >= r.interval {
+		r.report(now, false)
+	}
+
+	return n, err
+}
+
+func (r *progressReader) report(now time.Time, done bool) {
+	if r.done {
+		return
+	}
+
+	r.done = done
+	r.lastReport = now
+	elapsed := now.Sub(r.started)
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(r.sent) / elapsed.Seconds()
+	}
+
+	r.onProgress(UploadProgress{
+		BytesSent: r.sent,
+		Total:     r.total,
+		Elapsed:   elapsed,
+		Rate:      rate,
+		Done:      done,
+	})
+}
+
+// --------------------------------------------------------
+
+// IsCanceled returns true if the error was caused by the context of the
+// request being canceled or its deadline being exceeded.
+func (e *ImgurError) IsCanceled() bool {
+	return e.Kind == ErrorKindCanceled ||
+		errors.Is(e.Err, context.Canceled) ||
+		errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// Is reports whether target is the sentinel error of the kind of e,
+// e.g. errors.Is(err, ErrNotFound).
+func (e *ImgurError) Is(target error) bool {
+	return target != nil && target == e.Kind.getSentinel()
+}
+
+// Unwrap returns the inner error.
+func (e *ImgurError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ImgurError) Error() string {
+	myStr := ""
+	if e.Status != 0 {
+		myStr += "[" + ssg.ToBase10(int64(e.Status)) + "] : "
+	}
+
+	if e.Message != "" {
+		myStr += e.Message + " : "
+	}
+
+	if e.Err != nil {
+		myStr += e.Err.Error()
+	}
+
+	myStr = strings.Trim(strings.TrimSpace(myStr), ":")
 
 	return myStr
 }
+
+// --------------------------------------------------------
+
+func (p *RetryPolicy) getMaxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// isRetryable reports whether the attempt which got the response
+// (or failed with err) should be retried.
+func (p *RetryPolicy) isRetryable(res *http.Response, err error) bool {
+	if res == nil {
+		if p.RetryableError != nil {
+			return p.RetryableError(err)
+		}
+		return errors.Is(err, ErrTransport)
+	}
+
+	statuses := p.RetryableStatuses
+	if statuses == nil {
+		statuses = defaultRetryableStatuses
+	}
+	for _, status := range statuses {
+		if res.StatusCode == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getDelay returns the delay before the given retry, growing exponentially
+// from BaseDelay up to MaxDelay, or the duration asked for in the
+// Retry-After header of the response if it's longer.
+func (p *RetryPolicy) getDelay(retry int, res *http.Response) time.Duration {
+	delay, maxDelay := p.BaseDelay, p.MaxDelay
+	if delay <= 0 {
+		delay = DefaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+
+	for i := 0; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	if res != nil && !p.IgnoreRetryAfter {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+
+	return delay
+}
+
+// --------------------------------------------------------
+
+// String returns the name of the error kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindNotFound:
+		return "NotFound"
+	case ErrorKindUnauthorized:
+		return "Unauthorized"
+	case ErrorKindForbidden:
+		return "Forbidden"
+	case ErrorKindRateLimited:
+		return "RateLimited"
+	case ErrorKindServerError:
+		return "ServerError"
+	case ErrorKindDecode:
+		return "Decode"
+	case ErrorKindTransport:
+		return "Transport"
+	case ErrorKindValidation:
+		return "Validation"
+	case ErrorKindCanceled:
+		return "Canceled"
+	case ErrorKindBadRequest:
+		return "BadRequest"
+	}
+
+	return "Unknown"
+}
+
+func (k ErrorKind) getSentinel() error {
+	switch k {
+	case ErrorKindNotFound:
+		return ErrNotFound
+	case ErrorKindUnauthorized:
+		return ErrUnauthorized
+	case ErrorKindForbidden:
+		return ErrForbidden
+	case ErrorKindRateLimited:
+		return ErrRateLimited
+	case ErrorKindServerError:
+		return ErrServerError
+	case ErrorKindDecode:
+		return ErrDecode
+	case ErrorKindTransport:
+		return ErrTransport
+	case ErrorKindValidation:
+		return ErrValidation
+	case ErrorKindCanceled:
+		return ErrCanceled
+	case ErrorKindBadRequest:
+		return ErrBadRequest
+	}
+
+	return nil
+}
@@ -1,16 +1,48 @@
 package wotoImgur
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 )
 
-// Client used to for go-imgur
+// Client used to for go-imgur.
+// An ImgurClient is safe for concurrent use by multiple goroutines,
+// as long as its exported fields aren't modified after it's created.
+// A client created as a struct literal instead of by NewImgurClient uses
+// the defaults of ClientConfig, i.e. anonymous requests to imgur's api.
 type ImgurClient struct {
 	HTTPClient    *http.Client
 	ImgurClientID string
+	ClientSecret  string
 	RapidAPIKey   string
 
+	tokenSource       TokenSource
+	onTokenRotate     func(token *OAuthToken)
+	oauthAuthorizeURL string
+	oauthTokenURL     string
+	baseURL           string
+	uploadURL         string
+
+	retryPolicy     *RetryPolicy
+	rateLimitPolicy *RateLimitPolicy
+
+	// handler sends the requests through the middlewares.
+	handler Handler
+	logger  *slog.Logger
+
+	// mut guards the credits and the last rate limit.
+	mut     sync.Mutex
+	credits RateLimit
+
+	// refreshMut serializes refreshing the access token.
+	refreshMut sync.Mutex
+
 	lastRateLimit    *RateLimit
 	lastRateLimitErr error
 }
@@ -18,6 +50,181 @@ type ImgurClient struct {
 type ClientConfig struct {
 	HTTPClient  *http.Client
 	RapidAPIKey string
+
+	// ClientSecret is the client secret of the registered application,
+	// required for exchanging oauth2 codes and refreshing tokens.
+	ClientSecret string
+
+	// Token is an already obtained oauth2 token. When set, requests are
+	// signed with it instead of the client-id.
+	Token *OAuthToken
+
+	// TokenSource is consulted for the oauth2 token before every request.
+	// If nil, an in-memory source holding Token is used.
+	TokenSource TokenSource
+
+	// OnTokenRotate is called whenever the access token has been refreshed,
+	// so the new token can be persisted. It may be called from any goroutine
+	// sending a request, and may use the client itself.
+	OnTokenRotate func(token *OAuthToken)
+
+	// AuthorizeURL overrides imgur's oauth2 authorize endpoint.
+	AuthorizeURL string
+
+	// TokenURL overrides imgur's oauth2 token endpoint.
+	TokenURL string
+
+	// BaseURL overrides the root of imgur's api (e.g. "https://api.imgur.com/3/"),
+	// so requests can be sent to a proxy, a mirror or a test server.
+	BaseURL string
+
+	// UploadURL overrides the root of the api used for uploads.
+	// Defaults to BaseURL.
+	UploadURL string
+
+	// RetryPolicy controls how failed requests are retried.
+	// If nil, requests are never retried.
+	RetryPolicy *RetryPolicy
+
+	// RateLimitPolicy enables the rate limit governor of the client.
+	// If nil, requests are sent regardless of the remaining credits.
+	RateLimitPolicy *RateLimitPolicy
+
+	// Middlewares wrap the handler sending every request of the client,
+	// the first one being the outermost.
+	Middlewares []Middleware
+
+	// Logger, if set, receives the requests sent by the client along with
+	// their status, duration and remaining credits. Secrets such as tokens
+	// and keys are never logged.
+	Logger *slog.Logger
+}
+
+// Handler sends a single request to imgur and returns its response,
+// the way http.Client.Do does.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps the handler sending the requests of a client, so
+// logging, metrics, custom headers and such can be added once for all
+// endpoints. Middlewares see every attempt, including retries, and must
+// close the body of any response they don't return.
+type Middleware func(next Handler) Handler
+
+// apiRequest is a request sent through the pipeline of the client.
+type apiRequest struct {
+	method string
+	url    string
+
+	// form, if not nil, is sent url-encoded as the body of the request.
+	form url.Values
+
+	// newBody is called for every attempt and returns the body
+	// along with its content type.
+	newBody func() (io.ReadCloser, string, error)
+
+	// retryable is true if failed attempts can be sent again.
+	retryable bool
+
+	// anonymous requests are sent without credentials and don't
+	// count against the credits, e.g. oauth2 token requests.
+	anonymous bool
+}
+
+// RetryPolicy controls how requests failing with a transient error are retried.
+// Only GET, PUT and DELETE requests are retried, along with uploads whose
+// body can be sent again (e.g. files, or readers implementing io.Seeker).
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent,
+	// including the first attempt. Defaults to DefaultRetryMaxAttempts.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, which is doubled
+	// for every further retry. Defaults to DefaultRetryBaseDelay.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between two attempts. Defaults to DefaultRetryMaxDelay.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0 to 1) of each delay which is randomized,
+	// so concurrent clients don't retry all at once.
+	Jitter float64
+
+	// RetryableStatuses are the HTTP statuses which are retried.
+	// Defaults to 429, 500, 502, 503 and 504.
+	RetryableStatuses []int
+
+	// RetryableError reports whether a request failing with err should be
+	// retried. If nil, transport errors (see ErrTransport) are retried.
+	RetryableError func(err error) bool
+
+	// IgnoreRetryAfter disables waiting for the duration imgur asks for
+	// in the Retry-After header, when it's longer than the computed delay.
+	IgnoreRetryAfter bool
+
+	// OnRetry, if set, is called before waiting for every retry.
+	OnRetry func(info *RetryInfo)
+}
+
+// RetryInfo describes a failed attempt which is about to be retried.
+type RetryInfo struct {
+	// Attempt is the number of the failed attempt, starting from 1.
+	Attempt int
+
+	// Method is the method of the request.
+	Method string
+
+	// URL is the url of the request.
+	URL string
+
+	// Status is the HTTP status of the failed attempt, or 0 if
+	// no response has been received.
+	Status int
+
+	// Err is the error of the failed attempt, if no response has been received.
+	Err error
+
+	// Delay is the duration waited before the next attempt.
+	Delay time.Duration
+}
+
+// OAuthResponseType is the response_type passed to imgur's
+// oauth2 authorize endpoint.
+type OAuthResponseType string
+
+// OAuthToken contains the credentials returned by imgur's oauth2 token endpoint.
+type OAuthToken struct {
+	AccessToken     string    `json:"access_token"`     // The token used for signing requests on behalf of the user
+	RefreshToken    string    `json:"refresh_token"`    // The token used for obtaining a new access token
+	TokenType       string    `json:"token_type"`       // The type of the token, usually "bearer"
+	Scope           string    `json:"scope"`            // The scope of the token, if any
+	ExpiresIn       int64     `json:"expires_in"`       // The lifetime of the access token in seconds
+	AccountID       int64     `json:"account_id"`       // The account ID of the authorized user
+	AccountUsername string    `json:"account_username"` // The username of the authorized user
+	Expiry          time.Time `json:"expiry"`           // The time at which the access token expires
+}
+
+// TokenSource provides the oauth2 token the client signs its requests with.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns the current token, or nil if there is none.
+	Token() (*OAuthToken, error)
+
+	// SetToken replaces the current token, e.g. after it has been refreshed.
+	SetToken(token *OAuthToken) error
+}
+
+// MemoryTokenSource is a TokenSource which keeps the token in memory only.
+type MemoryTokenSource struct {
+	mut   *sync.Mutex
+	token *OAuthToken
+}
+
+// FileTokenSource is a TokenSource which persists the token as json
+// to a file on disk, so it survives restarts.
+type FileTokenSource struct {
+	mut   *sync.Mutex
+	path  string
+	token *OAuthToken
 }
 
 type ImgurError struct {
@@ -27,8 +234,249 @@ type ImgurError struct {
 	// Err is the inner error.
 	Err error
 
-	// Status is the status of the error.
+	// Status is the HTTP status of the failed request,
+	// or -1 if the request hasn't been answered by imgur.
 	Status int
+
+	// Kind is the kind of the error, which can also be checked
+	// using errors.Is with the sentinel errors, such as ErrNotFound.
+	Kind ErrorKind
+
+	// Payload is the error data returned by imgur, if any.
+	Payload *ErrorPayload
+}
+
+// ErrorKind is the kind of an ImgurError.
+type ErrorKind int
+
+// ErrorPayload is the error data imgur returns along with a failed request.
+type ErrorPayload struct {
+	// Error is the error message of imgur.
+	Error string
+
+	// Request is the path of the failed request.
+	Request string
+
+	// Method is the method of the failed request.
+	Method string
+}
+
+type errorDataWrapper struct {
+	Data    *errorData `json:"data"`
+	Success bool       `json:"success"`
+	Status  int        `json:"status"`
+}
+
+type errorData struct {
+	// Error is either a string, or an object containing the message.
+	Error   json.RawMessage `json:"error"`
+	Request string          `json:"request"`
+	Method  string          `json:"method"`
+}
+
+// UploadOptions contains the optional parameters of an upload.
+type UploadOptions struct {
+	// Album is the id of the album the upload is added to.
+	// For anonymous albums, it should be the deleteHash that is returned at creation.
+	Album string
+
+	// Title is the title of the upload.
+	Title string
+
+	// Description is the description of the upload.
+	Description string
+
+	// Name is the name of the uploaded file. It's also used for guessing
+	// the content type of the file.
+	Name string
+
+	// Size is the total size of the upload in bytes, used for progress reporting.
+	// If zero, it's determined from the reader when possible.
+	Size int64
+
+	// OnProgress, if set, is called periodically while the upload is being sent.
+	// It may be called from a different goroutine than the one uploading.
+	OnProgress func(progress UploadProgress)
+
+	// ProgressInterval is the minimum interval between two progress events.
+	// Defaults to DefaultProgressInterval.
+	ProgressInterval time.Duration
+
+	// DisableAudio strips the audio track of an uploaded video.
+	DisableAudio bool
+
+	// PollInterval is the interval in which a video is checked for having been
+	// processed by imgur. Defaults to DefaultVideoPollInterval.
+	PollInterval time.Duration
+
+	// ProcessingTimeout is the maximum time to wait for a video to be processed.
+	// Defaults to DefaultVideoProcessingTimeout.
+	ProcessingTimeout time.Duration
+}
+
+// UploadProgress describes how far an upload has gone.
+type UploadProgress struct {
+	// BytesSent is the number of bytes sent so far.
+	BytesSent int64
+
+	// Total is the total number of bytes to be sent, or -1 if unknown.
+	Total int64
+
+	// Elapsed is the time passed since the upload has started.
+	Elapsed time.Duration
+
+	// Rate is the average upload rate in bytes per second.
+	Rate float64
+
+	// Done is true for the last event of an upload.
+	Done bool
+}
+
+// progressReader reports the progress of reading the underlying reader.
+type progressReader struct {
+	reader     io.Reader
+	onProgress func(progress UploadProgress)
+	interval   time.Duration
+	total      int64
+	sent       int64
+	started    time.Time
+	lastReport time.Time
+	done       bool
+}
+
+// PageFetcher fetches the items of a page of a list endpoint, starting from page 0.
+type PageFetcher[T any] func(ctx context.Context, page int) ([]T, *RateLimit, error)
+
+// Pager lazily iterates over the items of a paginated list endpoint,
+// fetching the next page only once the items of the current one are used up.
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	maxItems int
+	page     int
+	items    []T
+	index    int
+	count    int
+	current  T
+	limit    *RateLimit
+	err      error
+	done     bool
+}
+
+type basicDataWrapper struct {
+	Data    bool `json:"data"`
+	Success bool `json:"success"`
+	Status  int  `json:"status"`
+}
+
+type stringDataWrapper struct {
+	Data    string `json:"data"`
+	Success bool   `json:"success"`
+	Status  int    `json:"status"`
+}
+
+// AlbumPrivacy is the privacy level of an album.
+type AlbumPrivacy string
+
+// AlbumLayout is the layout an album is displayed with.
+type AlbumLayout string
+
+// AlbumOptions contains the parameters of creating or updating an album.
+// Empty values are not sent.
+type AlbumOptions struct {
+	// Title is the title of the album.
+	Title string
+
+	// Description is the description of the album.
+	Description string
+
+	// Privacy is the privacy level of the album.
+	Privacy AlbumPrivacy
+
+	// Layout is the layout the album is displayed with.
+	Layout AlbumLayout
+
+	// Cover is the id of the image that should be the cover of the album.
+	Cover string
+
+	// IDs are the ids of the images the album contains.
+	// Only images owned by the authenticated user can be used.
+	IDs []string
+
+	// DeleteHashes are the deletehashes of the images the album contains,
+	// used for adding anonymous uploads to an anonymous album.
+	DeleteHashes []string
+}
+
+type accountDataWrapper struct {
+	Account *Account `json:"data"`
+	Success bool     `json:"success"`
+	Status  int      `json:"status"`
+}
+
+// Account contains the base information of an imgur account
+type Account struct {
+	ID             int        `json:"id"`              // The account id for the username requested.
+	URL            string     `json:"url"`             // The account username, will be the same as requested in the URL
+	Bio            string     `json:"bio"`             // A basic description the user has filled out
+	Avatar         string     `json:"avatar"`          // The link to the avatar of the account
+	Cover          string     `json:"cover"`           // The link to the cover image of the account
+	Reputation     float64    `json:"reputation"`      // The reputation for the account, in its numerical format.
+	ReputationName string     `json:"reputation_name"` // The name of the reputation level of the account
+	Created        int        `json:"created"`         // The epoch time of account creation
+	IsBlocked      bool       `json:"is_blocked"`      // True if the current user has blocked the account
+	Limit          *RateLimit // Current rate limit
+}
+
+type accountSettingsDataWrapper struct {
+	Settings *AccountSettings `json:"data"`
+	Success  bool             `json:"success"`
+	Status   int              `json:"status"`
+}
+
+// AccountSettings contains the settings of the authenticated account
+type AccountSettings struct {
+	AccountURL           string       `json:"account_url"`            // The username of the account
+	Email                string       `json:"email"`                  // The users email address
+	PublicImages         bool         `json:"public_images"`          // Automatically allow all images to be publicly accessible
+	AlbumPrivacy         AlbumPrivacy `json:"album_privacy"`          // Set the album privacy to this privacy setting on creation
+	AcceptedGalleryTerms bool         `json:"accepted_gallery_terms"` // True if the user has accepted the terms of uploading to the Imgur gallery.
+	ActiveEmails         []string     `json:"active_emails"`          // The email addresses that have been activated to allow uploading
+	MessagingEnabled     bool         `json:"messaging_enabled"`      // If the user is accepting incoming messages or not
+	ShowMature           bool         `json:"show_mature"`            // True if the user has opted to have mature images displayed in gallery list endpoints.
+	FirstParty           bool         `json:"first_party"`            // True unless the user created their account via a third party service such as Google Plus.
+	Limit                *RateLimit   // Current rate limit
+}
+
+// AccountSettingsOptions contains the account settings to be changed.
+// Empty and nil values are left unchanged.
+type AccountSettingsOptions struct {
+	Bio                  string       // The biography of the user, is displayed in the gallery profile page.
+	PublicImages         *bool        // Set the users images to private or public by default
+	MessagingEnabled     *bool        // Allows the user to enable or disable private messages
+	AlbumPrivacy         AlbumPrivacy // Sets the default privacy level of albums the users creates
+	AcceptedGalleryTerms *bool        // The user agreement to the Imgur Gallery terms.
+	Username             string       // A valid Imgur username (between 4 and 63 alphanumeric characters)
+	ShowMature           *bool        // Toggle display of mature images in gallery list endpoints.
+	NewsletterSubscribed *bool        // Toggle subscription to email newsletter.
+}
+
+type albumListDataWrapper struct {
+	Albums  []AlbumInfo `json:"data"`
+	Success bool        `json:"success"`
+	Status  int         `json:"status"`
+}
+
+type idListDataWrapper struct {
+	IDs     []string `json:"data"`
+	Success bool     `json:"success"`
+	Status  int      `json:"status"`
+}
+
+type countDataWrapper struct {
+	Count   int  `json:"data"`
+	Success bool `json:"success"`
+	Status  int  `json:"status"`
 }
 
 type albumInfoDataWrapper struct {
@@ -63,6 +511,34 @@ type AlbumInfo struct {
 	Limit       *RateLimit  // Current rate limit
 }
 
+// CommentSort is the order comments are sorted in.
+type CommentSort string
+
+// Vote is a vote on a comment or a gallery item.
+type Vote string
+
+type commentDataWrapper struct {
+	Comment *Comment `json:"data"`
+	Success bool     `json:"success"`
+	Status  int      `json:"status"`
+}
+
+type commentListDataWrapper struct {
+	Comments []Comment `json:"data"`
+	Success  bool      `json:"success"`
+	Status   int       `json:"status"`
+}
+
+type commentIDDataWrapper struct {
+	Data    *commentIDData `json:"data"`
+	Success bool           `json:"success"`
+	Status  int            `json:"status"`
+}
+
+type commentIDData struct {
+	ID int `json:"id"`
+}
+
 // Comment is an imgur comment
 type Comment struct {
 	ID         int       `json:"id"`          // The ID for the comment
@@ -92,6 +568,140 @@ type GenericInfo struct {
 	Limit  *RateLimit
 }
 
+// GallerySection is a section of the gallery.
+type GallerySection string
+
+// GallerySort is the order gallery items are sorted in.
+type GallerySort string
+
+// GalleryWindow is the date range of the top gallery items.
+type GalleryWindow string
+
+// GalleryItem is an entry of a gallery list, which is either an image
+// or an album. Only one pointer is != nil.
+type GalleryItem struct {
+	Image *GalleryImageInfo
+	Album *GalleryAlbumInfo
+}
+
+// SearchType is the file type gallery search results are restricted to.
+type SearchType string
+
+// SearchSize is the image size gallery search results are restricted to.
+type SearchSize string
+
+// SearchQuery describes a gallery search. Use NewSearchQuery to create one
+// and chain its methods for the advanced parameters.
+type SearchQuery struct {
+	query   string
+	all     []string
+	any     []string
+	exactly string
+	not     []string
+	qType   SearchType
+	size    SearchSize
+	sort    GallerySort
+	window  GalleryWindow
+	page    int
+}
+
+type galleryItemDataWrapper struct {
+	Item    *GalleryItem `json:"data"`
+	Success bool         `json:"success"`
+	Status  int          `json:"status"`
+}
+
+type galleryVotesDataWrapper struct {
+	Votes   *GalleryVotes `json:"data"`
+	Success bool          `json:"success"`
+	Status  int           `json:"status"`
+}
+
+// GalleryVotes contains the vote counts of a gallery item
+type GalleryVotes struct {
+	Ups   int        `json:"ups"`   // Number of upvotes
+	Downs int        `json:"downs"` // Number of downvotes
+	Limit *RateLimit // Current rate limit
+}
+
+type tagDataWrapper struct {
+	Tag     *Tag `json:"data"`
+	Success bool `json:"success"`
+	Status  int  `json:"status"`
+}
+
+// Tag contains the information of a gallery tag provided by imgur
+type Tag struct {
+	Name                 string        `json:"name"`                   // Name of the tag
+	DisplayName          string        `json:"display_name"`           // Display name of the tag
+	Followers            int           `json:"followers"`              // Number of followers of the tag
+	TotalItems           int           `json:"total_items"`            // Total number of gallery items tagged
+	Following            bool          `json:"following"`              // True if the authenticated user is following the tag
+	IsWhitelisted        bool          `json:"is_whitelisted"`         // True if the tag is whitelisted
+	BackgroundHash       string        `json:"background_hash"`        // Image ID of the background of the tag
+	ThumbnailHash        string        `json:"thumbnail_hash"`         // Image ID of the thumbnail of the tag
+	Accent               string        `json:"accent"`                 // Accent color of the tag
+	BackgroundIsAnimated bool          `json:"background_is_animated"` // True if the background is animated
+	ThumbnailIsAnimated  bool          `json:"thumbnail_is_animated"`  // True if the thumbnail is animated
+	IsPromoted           bool          `json:"is_promoted"`            // True if the tag is promoted
+	Description          string        `json:"description"`            // Description of the tag
+	Items                []GalleryItem `json:"items,omitempty"`        // OPTIONAL, the gallery items of the tag, only available when requesting a single tag
+	Limit                *RateLimit    // Current rate limit
+}
+
+type tagsInfoDataWrapper struct {
+	Info    *TagsInfo `json:"data"`
+	Success bool      `json:"success"`
+	Status  int       `json:"status"`
+}
+
+// TagsInfo contains the tags listed by imgur
+type TagsInfo struct {
+	Tags     []Tag      `json:"tags"`     // The default tags
+	Featured string     `json:"featured"` // The name of the featured tag
+	Limit    *RateLimit // Current rate limit
+}
+
+type tagVotesDataWrapper struct {
+	Data    *tagVotesData `json:"data"`
+	Success bool          `json:"success"`
+	Status  int           `json:"status"`
+}
+
+type tagVotesData struct {
+	Tags []TagVote `json:"tags"`
+}
+
+// TagVote contains the votes of a tag on a gallery item
+type TagVote struct {
+	Name   string `json:"name"`   // Name of the tag
+	Author string `json:"author"` // Username of the user who tagged the item
+	Ups    int    `json:"ups"`    // Number of upvotes of the tag
+	Downs  int    `json:"downs"`  // Number of downvotes of the tag
+}
+
+type topicListDataWrapper struct {
+	Topics  []Topic `json:"data"`
+	Success bool    `json:"success"`
+	Status  int     `json:"status"`
+}
+
+// Topic contains the information of a gallery topic provided by imgur
+type Topic struct {
+	ID          int          `json:"id"`          // ID of the topic
+	Name        string       `json:"name"`        // Name of the topic
+	Description string       `json:"description"` // Description of the topic
+	CSS         string       `json:"css"`         // CSS class used on the website to style the topic
+	Ephemeral   bool         `json:"ephemeral"`   // True if the topic is only temporary
+	TopPost     *GalleryItem `json:"topPost"`     // The top gallery item of the topic
+}
+
+type galleryListDataWrapper struct {
+	Items   []GalleryItem `json:"data"`
+	Success bool          `json:"success"`
+	Status  int           `json:"status"`
+}
+
 type galleryAlbumInfoDataWrapper struct {
 	Ai      *GalleryAlbumInfo `json:"data"`
 	Success bool              `json:"success"`
@@ -173,6 +783,12 @@ type GalleryImageInfo struct {
 	Limit        *RateLimit // Current rate limit
 }
 
+type imageListDataWrapper struct {
+	Images  []ImageInfo `json:"data"`
+	Success bool        `json:"success"`
+	Status  int         `json:"status"`
+}
+
 type imageInfoDataWrapper struct {
 	Info    *ImageInfo `json:"data"`
 	Success bool       `json:"success"`
@@ -181,30 +797,37 @@ type imageInfoDataWrapper struct {
 
 // ImageInfo contains all image information provided by imgur
 type ImageInfo struct {
-	ID          string     `json:"id"`                   // The ID for the image
-	Title       string     `json:"title"`                // The title of the image.
-	Description string     `json:"description"`          // Description of the image.
-	Datetime    int        `json:"datetime"`             // Time uploaded, epoch time
-	MimeType    string     `json:"type"`                 // Image MIME type.
-	Animated    bool       `json:"animated"`             // is the image animated
-	Width       int        `json:"width"`                // The width of the image in pixels
-	Height      int        `json:"height"`               // The height of the image in pixels
-	Size        int        `json:"size"`                 // The size of the image in bytes
-	Views       int        `json:"views"`                // The number of image views
-	Bandwidth   int        `json:"bandwidth"`            // Bandwidth consumed by the image in bytes
-	DeleteHash  string     `json:"deletehash,omitempty"` // OPTIONAL, the deletehash, if you're logged in as the image owner
-	Name        string     `json:"name,omitempty"`       // OPTIONAL, the original filename, if you're logged in as the image owner
-	Section     string     `json:"section"`              // If the image has been categorized by our backend then this will contain the section the image belongs in. (funny, cats, wtf, etc)
-	Link        string     `json:"link"`                 // The direct link to the the image. (Note: if fetching an animated GIF that was over 20MB in original size, a .gif thumbnail will be returned)
-	Gifv        string     `json:"gifv,omitempty"`       // OPTIONAL, The .gifv link. Only available if the image is animated and type is 'image/gif'.
-	Mp4         string     `json:"mp4,omitempty"`        // OPTIONAL, The direct link to the .mp4. Only available if the image is animated and type is 'image/gif'.
-	Mp4Size     int        `json:"mp4_size,omitempty"`   // OPTIONAL, The Content-Length of the .mp4. Only available if the image is animated and type is 'image/gif'. Note that a zero value (0) is possible if the video has not yet been generated
-	Looping     bool       `json:"looping,omitempty"`    // OPTIONAL, Whether the image has a looping animation. Only available if the image is animated and type is 'image/gif'.
-	Favorite    bool       `json:"favorite"`             // Indicates if the current user favorited the image. Defaults to false if not signed in.
-	Nsfw        bool       `json:"nsfw"`                 // Indicates if the image has been marked as nsfw or not. Defaults to null if information is not available.
-	Vote        string     `json:"vote"`                 // The current user's vote on the album. null if not signed in, if the user hasn't voted on it, or if not submitted to the gallery.
-	InGallery   bool       `json:"in_gallery"`           // True if the image has been submitted to the gallery, false if otherwise.
-	Limit       *RateLimit // Current rate limit
+	ID          string          `json:"id"`                   // The ID for the image
+	Title       string          `json:"title"`                // The title of the image.
+	Description string          `json:"description"`          // Description of the image.
+	Datetime    int             `json:"datetime"`             // Time uploaded, epoch time
+	MimeType    string          `json:"type"`                 // Image MIME type.
+	Animated    bool            `json:"animated"`             // is the image animated
+	Width       int             `json:"width"`                // The width of the image in pixels
+	Height      int             `json:"height"`               // The height of the image in pixels
+	Size        int             `json:"size"`                 // The size of the image in bytes
+	Views       int             `json:"views"`                // The number of image views
+	Bandwidth   int             `json:"bandwidth"`            // Bandwidth consumed by the image in bytes
+	DeleteHash  string          `json:"deletehash,omitempty"` // OPTIONAL, the deletehash, if you're logged in as the image owner
+	Name        string          `json:"name,omitempty"`       // OPTIONAL, the original filename, if you're logged in as the image owner
+	Section     string          `json:"section"`              // If the image has been categorized by our backend then this will contain the section the image belongs in. (funny, cats, wtf, etc)
+	Link        string          `json:"link"`                 // The direct link to the the image. (Note: if fetching an animated GIF that was over 20MB in original size, a .gif thumbnail will be returned)
+	Gifv        string          `json:"gifv,omitempty"`       // OPTIONAL, The .gifv link. Only available if the image is animated and type is 'image/gif'.
+	Mp4         string          `json:"mp4,omitempty"`        // OPTIONAL, The direct link to the .mp4. Only available if the image is animated and type is 'image/gif'.
+	Mp4Size     int             `json:"mp4_size,omitempty"`   // OPTIONAL, The Content-Length of the .mp4. Only available if the image is animated and type is 'image/gif'. Note that a zero value (0) is possible if the video has not yet been generated
+	Looping     bool            `json:"looping,omitempty"`    // OPTIONAL, Whether the image has a looping animation. Only available if the image is animated and type is 'image/gif'.
+	Favorite    bool            `json:"favorite"`             // Indicates if the current user favorited the image. Defaults to false if not signed in.
+	Nsfw        bool            `json:"nsfw"`                 // Indicates if the image has been marked as nsfw or not. Defaults to null if information is not available.
+	Vote        string          `json:"vote"`                 // The current user's vote on the album. null if not signed in, if the user hasn't voted on it, or if not submitted to the gallery.
+	InGallery   bool            `json:"in_gallery"`           // True if the image has been submitted to the gallery, false if otherwise.
+	HasSound    bool            `json:"has_sound"`            // True if the uploaded video has an audio track.
+	Processing  *ProcessingInfo `json:"processing,omitempty"` // OPTIONAL, The processing state of an uploaded video.
+	Limit       *RateLimit      // Current rate limit
+}
+
+// ProcessingInfo describes the state of a video being processed by imgur.
+type ProcessingInfo struct {
+	Status string `json:"status"` // pending, completed or failed
 }
 
 type rateLimitDataWrapper struct {
@@ -234,4 +857,37 @@ type RateLimit struct {
 	ClientLimit int64
 	// Total credits remaining for the application in a day.
 	ClientRemaining int64
+	// Total POST requests that can be sent in an hour, only set for POST requests.
+	PostLimit int64
+	// Total POST requests remaining in the current hour.
+	PostRemaining int64
+	// Timestamp for when the POST requests will be reset.
+	PostReset time.Time
+}
+
+// RateLimitPolicy configures the governor of the client, which keeps track
+// of the credits reported by imgur and holds requests back before they
+// run out, instead of getting the client or user banned.
+type RateLimitPolicy struct {
+	// MinUserRemaining is the amount of user credits kept in reserve;
+	// once the remaining credits drop to it, requests wait for UserReset.
+	MinUserRemaining int64
+
+	// MinClientRemaining is the amount of application credits kept in reserve.
+	// Imgur doesn't tell when they're reset, so once the remaining credits
+	// drop to it, requests always fail with ErrRateLimited.
+	MinClientRemaining int64
+
+	// MinPostRemaining is the amount of POST requests kept in reserve;
+	// once the remaining ones drop to it, POST requests wait for PostReset.
+	MinPostRemaining int64
+
+	// FailFast makes requests fail with ErrRateLimited instead of
+	// waiting for the credits to be reset.
+	FailFast bool
+
+	// MaxWait is the longest a request waits for the credits to be reset.
+	// If the reset is further away, the request fails with ErrRateLimited.
+	// Zero means no limit, apart from the context of the request.
+	MaxWait time.Duration
 }
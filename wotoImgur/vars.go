@@ -1 +1,44 @@
 package wotoImgur
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// multipartEscaper escapes the parameters of a Content-Disposition
+// header, the same way mime/multipart does.
+var multipartEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// videoContentTypes maps the file extensions of the video formats
+// accepted by imgur to their content type.
+var videoContentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+}
+
+// defaultRetryableStatuses are the statuses retried when a RetryPolicy
+// doesn't specify its own.
+var defaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// sentinel errors matching the kinds of an ImgurError, to be used with errors.Is.
+var (
+	ErrNotFound     = errors.New("imgur: not found")
+	ErrUnauthorized = errors.New("imgur: unauthorized")
+	ErrForbidden    = errors.New("imgur: forbidden")
+	ErrRateLimited  = errors.New("imgur: rate limited")
+	ErrServerError  = errors.New("imgur: server error")
+	ErrDecode       = errors.New("imgur: decode error")
+	ErrTransport    = errors.New("imgur: transport error")
+	ErrValidation   = errors.New("imgur: validation error")
+	ErrCanceled     = errors.New("imgur: canceled")
+	ErrBadRequest   = errors.New("imgur: bad request")
+)
@@ -0,0 +1,53 @@
+package wotoImgurtest
+
+import "time"
+
+// DefaultAccount is the username of the account every bearer token
+// sent to the fake server is authenticated as.
+const DefaultAccount = "wototest"
+
+// default credits of the fake server, the same as the ones of imgur.
+const (
+	DefaultUserLimit   = 12500
+	DefaultClientLimit = 12500
+	DefaultPostLimit   = 1250
+)
+
+// DefaultResetInterval is the duration after which the credits of
+// the fake server are reset.
+const DefaultResetInterval = time.Hour
+
+// costs of requests, in credits. Uploads cost more than other requests,
+// like they do on imgur.
+const (
+	requestCost = 1
+	uploadCost  = 10
+)
+
+// GalleryPageSize is the number of items in a page of the fake gallery.
+const GalleryPageSize = 60
+
+const (
+	idLength         = 7
+	deleteHashLength = 15
+	idAlphabet       = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	linkPrefix       = "https://i.imgur.com/"
+	albumLinkPrefix  = "https://imgur.com/a/"
+)
+
+// modes of a Recorder.
+const (
+	// ModeRecord sends the requests through the underlying transport and
+	// records them to the cassette, replacing any existing one.
+	ModeRecord RecorderMode = iota
+
+	// ModeReplay answers the requests from the cassette, without sending
+	// anything; requests missing from the cassette fail.
+	ModeReplay
+
+	// ModeAuto replays the cassette if the file exists, and records it otherwise.
+	ModeAuto
+)
+
+// RedactedValue replaces the secrets in the recorded interactions.
+const RedactedValue = "REDACTED"
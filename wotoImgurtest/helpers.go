@@ -0,0 +1,404 @@
+package wotoImgurtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math/rand"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewServer starts a fake imgur api with empty storage and full credits.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		mut:     &sync.Mutex{},
+		images:  make(map[string]*storedImage),
+		albums:  make(map[string]*storedAlbum),
+		gallery: make(map[string]*galleryEntry),
+	}
+	s.resetCredits(DefaultUserLimit, DefaultClientLimit, DefaultPostLimit)
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// NewRecorder creates a recorder for the cassette file at path. In ModeRecord,
+// requests are sent through transport (http.DefaultTransport if nil).
+// In ModeReplay, the cassette has to exist already.
+func NewRecorder(path string, mode RecorderMode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+		mut:       &sync.Mutex{},
+		cassette:  &Cassette{},
+	}
+
+	if mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("wotoImgurtest: could not read cassette %v: %w", path, err)
+		}
+		if err = json.Unmarshal(b, r.cassette); err != nil {
+			return nil, fmt.Errorf("wotoImgurtest: could not decode cassette %v: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// recordRequest reads the body of the request and returns it along with
+// the redacted record of the request.
+func recordRequest(req *http.Request) (*RecordedRequest, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	form, err := parseRecordedForm(req, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the query is part of the form too, so it's redacted the same way.
+	recordedURL := *req.URL
+	recordedURL.RawQuery = redactForm(recordedURL.Query()).Encode()
+
+	recorded := &RecordedRequest{
+		Method:  req.Method,
+		URL:     recordedURL.String(),
+		Path:    req.URL.Path,
+		Headers: redactHeader(req.Header),
+		Form:    redactForm(form),
+	}
+
+	return recorded, body, nil
+}
+
+// parseRecordedForm returns the query parameters of the request along
+// with the fields of its url-encoded or multipart body. Uploaded files
+// are replaced by their sha256 hash.
+func parseRecordedForm(req *http.Request, body []byte) (url.Values, error) {
+	form := req.URL.Query()
+
+	mediaType, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			form[key] = append(form[key], value...)
+		}
+	case "multipart/form-data":
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+
+			value := string(content)
+			if part.FileName() != "" {
+				sum := sha256.Sum256(content)
+				value = "sha256:" + hex.EncodeToString(sum[:])
+			}
+			form.Add(part.FormName(), value)
+		}
+	}
+
+	if len(form) == 0 {
+		return nil, nil
+	}
+	return form, nil
+}
+
+func redactHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, key := range redactedHeaders {
+		if redacted.Get(key) != "" {
+			redacted.Set(key, RedactedValue)
+		}
+	}
+	return redacted
+}
+
+func redactForm(form url.Values) url.Values {
+	for _, key := range redactedFields {
+		if _, ok := form[key]; ok {
+			form.Set(key, RedactedValue)
+		}
+	}
+	return form
+}
+
+// redactBody redacts the secret fields of a JSON response body,
+// returning other bodies untouched.
+func redactBody(body []byte) []byte {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return body
+	}
+
+	redacted := false
+	for _, key := range redactedBodyFields {
+		if _, ok := fields[key]; ok {
+			fields[key], _ = json.Marshal(RedactedValue)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// parseRequest parses the path, the authorization and the form
+// (url-encoded or multipart) of a request to the fake api.
+func parseRequest(r *http.Request) (*request, *apiError) {
+	path := strings.TrimPrefix(r.URL.Path, "/3/")
+	if path == r.URL.Path {
+		return nil, newAPIError(http.StatusNotFound, "Unknown api version")
+	}
+
+	req := &request{
+		method:   r.Method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+	}
+
+	auth := r.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(auth, "Bearer ") && len(auth) > len("Bearer "):
+		req.account = DefaultAccount
+	case strings.HasPrefix(auth, "Client-ID ") && len(auth) > len("Client-ID "):
+	default:
+		return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if err := r.ParseForm(); err != nil {
+			return nil, newAPIError(http.StatusBadRequest, "Invalid form: "+err.Error())
+		}
+		req.form = r.Form
+		return req, nil
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, "Invalid multipart form: "+err.Error())
+	}
+	req.form = r.MultipartForm.Value
+
+	for _, field := range []string{"image", "video"} {
+		files := r.MultipartForm.File[field]
+		if len(files) == 0 {
+			continue
+		}
+
+		f, err := files[0].Open()
+		if err != nil {
+			return nil, newAPIError(http.StatusBadRequest, "Invalid file: "+err.Error())
+		}
+		req.file, err = io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, newAPIError(http.StatusBadRequest, "Invalid file: "+err.Error())
+		}
+
+		req.fileName = files[0].Filename
+		req.fileType = files[0].Header.Get("Content-Type")
+		break
+	}
+
+	return req, nil
+}
+
+// getUploadData returns the content of an uploaded file, which has been
+// sent either as a multipart file or as a base64/URL form field.
+func getUploadData(req *request) ([]byte, string, *apiError) {
+	if req.file != nil {
+		return req.file, req.fileType, nil
+	}
+
+	value := req.form.Get("image")
+	if value == "" {
+		value = req.form.Get("video")
+	}
+	if value == "" {
+		return nil, "", newAPIError(http.StatusBadRequest, "No image data was sent to the upload api")
+	}
+
+	switch req.form.Get("type") {
+	case "URL", "url":
+		return []byte(value), getContentType(value), nil
+	case "file":
+		return []byte(value), "", nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, "", newAPIError(http.StatusBadRequest, "Invalid base64 image data")
+	}
+
+	return data, "", nil
+}
+
+// getContentType returns the content type of a file by its name.
+func getContentType(name string) string {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if i := strings.Index(contentType, ";"); i != -1 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// getExtension returns the extension used in the links of a content type.
+func getExtension(contentType string) string {
+	switch contentType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "video/mp4", "video/quicktime", "video/webm":
+		return ".mp4"
+	}
+	return ".png"
+}
+
+// decodeImageSize returns the dimensions of an image, or zeros
+// if the data isn't an image in a known format.
+func decodeImageSize(data []byte) (int, int, string) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, ""
+	}
+	return config.Width, config.Height, "image/" + format
+}
+
+// toggleFavorite toggles the favorite state of an image or album for
+// the account of the request.
+func toggleFavorite(req *request, favorites map[string]bool) (any, *apiError) {
+	if req.account == "" {
+		return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+	}
+
+	if favorites[req.account] {
+		delete(favorites, req.account)
+		return "unfavorited", nil
+	}
+
+	favorites[req.account] = true
+	return "favorited", nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStrings returns values without the removed ones.
+func removeStrings(values []string, removed ...string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if !containsString(removed, v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func newID(length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = idAlphabet[rand.Intn(len(idAlphabet))]
+	}
+	return string(b)
+}
+
+func newAPIError(status int, message string) *apiError {
+	return &apiError{
+		status:  status,
+		message: message,
+	}
+}
+
+// writeData writes data the way imgur wraps the result of a request.
+func writeData(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&dataWrapper{
+		Data:    data,
+		Success: true,
+		Status:  http.StatusOK,
+	})
+}
+
+// writeError writes the error the way imgur does, including
+// the path and method of the failed request.
+func writeError(w http.ResponseWriter, r *http.Request, err *apiError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.status)
+	_ = json.NewEncoder(w).Encode(&dataWrapper{
+		Data: &errorData{
+			Error:   err.message,
+			Request: r.URL.Path,
+			Method:  r.Method,
+		},
+		Status: err.status,
+	})
+}
+
+func nowUnix() int {
+	return int(time.Now().Unix())
+}
@@ -0,0 +1,1110 @@
+package wotoImgurtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ALiwoto/wotoImgur/wotoImgur"
+)
+
+// BaseURL returns the root of the fake api, to be used as the BaseURL
+// of a wotoImgur.ClientConfig.
+func (s *Server) BaseURL() string {
+	return s.Server.URL + "/3/"
+}
+
+// NewImgurClient creates a client sending all of its requests to the fake
+// api. config may be nil; its BaseURL and UploadURL are overridden, and if it
+// has no HTTPClient, the one of the test server is used.
+func (s *Server) NewImgurClient(clientID string, config *wotoImgur.ClientConfig) (*wotoImgur.ImgurClient, error) {
+	var clientConfig wotoImgur.ClientConfig
+	if config != nil {
+		clientConfig = *config
+	}
+
+	clientConfig.BaseURL = s.BaseURL()
+	clientConfig.UploadURL = ""
+	if clientConfig.HTTPClient == nil {
+		clientConfig.HTTPClient = s.Client()
+	}
+
+	return wotoImgur.NewImgurClient(clientID, &clientConfig)
+}
+
+// AddImage stores an anonymous image with the given content and returns it
+// along with its deletehash. If info has no ID, a new one is generated.
+func (s *Server) AddImage(info wotoImgur.ImageInfo, data []byte) *wotoImgur.ImageInfo {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.addImage(info, data, "")
+}
+
+// AddAlbum stores an anonymous album containing the given images and returns
+// it along with its deletehash. If info has no ID, a new one is generated.
+func (s *Server) AddAlbum(info wotoImgur.AlbumInfo, imageIDs ...string) *wotoImgur.AlbumInfo {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	album := s.addAlbum(info, "")
+	album.imageIDs = append(album.imageIDs, imageIDs...)
+
+	return s.getAlbumView(album, "", true)
+}
+
+// AddToGallery submits a stored image or album to the gallery with the title
+// and tags. It returns false if there is no image or album with the id.
+func (s *Server) AddToGallery(id, title string, tags ...string) bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	_, isImage := s.images[id]
+	_, isAlbum := s.albums[id]
+	if !isImage && !isAlbum {
+		return false
+	}
+
+	s.gallery[id] = &galleryEntry{
+		id:       id,
+		isAlbum:  isAlbum,
+		title:    title,
+		tags:     tags,
+		datetime: nowUnix(),
+		votes:    make(map[string]wotoImgur.Vote),
+	}
+
+	return true
+}
+
+// Image returns the stored image with the id, including its deletehash.
+func (s *Server) Image(id string) (*wotoImgur.ImageInfo, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	img, ok := s.images[id]
+	if !ok {
+		return nil, false
+	}
+
+	return s.getImageView(img, "", true), true
+}
+
+// ImageData returns the content uploaded for the image with the id.
+func (s *Server) ImageData(id string) ([]byte, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	img, ok := s.images[id]
+	if !ok {
+		return nil, false
+	}
+
+	return append([]byte(nil), img.data...), true
+}
+
+// Album returns the stored album with the id, including its deletehash.
+func (s *Server) Album(id string) (*wotoImgur.AlbumInfo, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	album, ok := s.albums[id]
+	if !ok {
+		return nil, false
+	}
+
+	return s.getAlbumView(album, "", true), true
+}
+
+// InGallery returns true if the image or album with the id is in the gallery.
+func (s *Server) InGallery(id string) bool {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	_, ok := s.gallery[id]
+	return ok
+}
+
+// SetCredits sets the remaining credits of the user, the application and
+// POST requests. Requests fail with 429 once the credits they need run out.
+func (s *Server) SetCredits(userRemaining, clientRemaining, postRemaining int64) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.userRemaining = userRemaining
+	s.clientRemaining = clientRemaining
+	s.postRemaining = postRemaining
+}
+
+// ResetCredits restores all credits to their limits.
+func (s *Server) ResetCredits() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.resetCredits(s.userLimit, s.clientLimit, s.postLimit)
+}
+
+// Requests returns the number of requests the fake api has received.
+func (s *Server) Requests() int {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return s.requests
+}
+
+// ServeHTTP handles a request to the fake api.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, apiErr := parseRequest(r)
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.requests++
+	if apiErr == nil {
+		apiErr = s.chargeCredits(req)
+	}
+	s.writeRateLimitHeaders(w, r.Method)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+
+	data, apiErr := s.route(req)
+	if apiErr != nil {
+		writeError(w, r, apiErr)
+		return
+	}
+
+	writeData(w, data)
+}
+
+func (s *Server) resetCredits(userLimit, clientLimit, postLimit int64) {
+	s.userLimit, s.userRemaining = userLimit, userLimit
+	s.clientLimit, s.clientRemaining = clientLimit, clientLimit
+	s.postLimit, s.postRemaining = postLimit, postLimit
+	s.reset = time.Now().Add(DefaultResetInterval)
+}
+
+// chargeCredits takes the credits needed by the request, or returns
+// an error if there aren't enough of them left.
+func (s *Server) chargeCredits(req *request) *apiError {
+	if time.Now().After(s.reset) {
+		s.resetCredits(s.userLimit, s.clientLimit, s.postLimit)
+	}
+
+	var cost int64 = requestCost
+	if req.isUpload() {
+		cost = uploadCost
+	}
+
+	if s.userRemaining < cost || s.clientRemaining < cost {
+		return newAPIError(http.StatusTooManyRequests, "Too Many Requests")
+	}
+	if req.method == http.MethodPost && s.postRemaining <= 0 {
+		return newAPIError(http.StatusTooManyRequests, "Too Many Requests")
+	}
+
+	s.userRemaining -= cost
+	s.clientRemaining -= cost
+	if req.method == http.MethodPost {
+		s.postRemaining--
+	}
+
+	return nil
+}
+
+func (s *Server) writeRateLimitHeaders(w http.ResponseWriter, method string) {
+	h := w.Header()
+	h.Set("X-RateLimit-UserLimit", strconv.FormatInt(s.userLimit, 10))
+	h.Set("X-RateLimit-UserRemaining", strconv.FormatInt(s.userRemaining, 10))
+	h.Set("X-RateLimit-UserReset", strconv.FormatInt(s.reset.Unix(), 10))
+	h.Set("X-RateLimit-ClientLimit", strconv.FormatInt(s.clientLimit, 10))
+	h.Set("X-RateLimit-ClientRemaining", strconv.FormatInt(s.clientRemaining, 10))
+
+	if method == http.MethodPost {
+		h.Set("X-Post-Rate-Limit-Limit", strconv.FormatInt(s.postLimit, 10))
+		h.Set("X-Post-Rate-Limit-Remaining", strconv.FormatInt(s.postRemaining, 10))
+		h.Set("X-Post-Rate-Limit-Reset", strconv.Itoa(int(time.Until(s.reset).Seconds())))
+	}
+}
+
+// route dispatches the request to its handler by its method and path.
+func (s *Server) route(req *request) (any, *apiError) {
+	segs := req.segments
+	switch segs[0] {
+	case "image", "upload":
+		return s.routeImage(req, segs)
+	case "album":
+		return s.routeAlbum(req, segs)
+	case "gallery":
+		return s.routeGallery(req, segs)
+	case "account":
+		if len(segs) == 2 && req.method == http.MethodGet {
+			return s.getAccount(req, segs[1])
+		}
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find the requested endpoint")
+}
+
+func (s *Server) routeImage(req *request, segs []string) (any, *apiError) {
+	switch {
+	case req.isUpload():
+		return s.upload(req)
+	case len(segs) == 2 && segs[0] == "image":
+		switch req.method {
+		case http.MethodGet:
+			img, ok := s.images[segs[1]]
+			if !ok {
+				return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the id, "+segs[1])
+			}
+			return s.getImageView(img, req.account, false), nil
+		case http.MethodPost:
+			return s.updateImage(req, segs[1])
+		case http.MethodDelete:
+			return s.deleteImage(req, segs[1])
+		}
+	case len(segs) == 3 && segs[2] == "favorite" && req.method == http.MethodPost:
+		img, ok := s.images[segs[1]]
+		if !ok {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the id, "+segs[1])
+		}
+		return toggleFavorite(req, img.favorites)
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find the requested endpoint")
+}
+
+func (s *Server) routeAlbum(req *request, segs []string) (any, *apiError) {
+	switch {
+	case len(segs) == 1 && req.method == http.MethodPost:
+		return s.createAlbum(req)
+	case len(segs) == 2:
+		switch req.method {
+		case http.MethodGet:
+			album, ok := s.albums[segs[1]]
+			if !ok {
+				return nil, newAPIError(http.StatusNotFound, "Unable to find an album with the id, "+segs[1])
+			}
+			return s.getAlbumView(album, req.account, false), nil
+		case http.MethodPut:
+			return s.updateAlbum(req, segs[1])
+		case http.MethodPost:
+			return s.changeAlbumImages(req, segs[1], "set")
+		case http.MethodDelete:
+			return s.deleteAlbum(req, segs[1])
+		}
+	case len(segs) == 3 && req.method == http.MethodGet && segs[2] == "images":
+		album, ok := s.albums[segs[1]]
+		if !ok {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find an album with the id, "+segs[1])
+		}
+		return s.getAlbumView(album, req.account, false).Images, nil
+	case len(segs) == 3 && req.method == http.MethodPost:
+		switch segs[2] {
+		case "add", "remove_images":
+			return s.changeAlbumImages(req, segs[1], segs[2])
+		case "favorite":
+			album, ok := s.albums[segs[1]]
+			if !ok {
+				return nil, newAPIError(http.StatusNotFound, "Unable to find an album with the id, "+segs[1])
+			}
+			return toggleFavorite(req, album.favorites)
+		}
+	case len(segs) == 4 && req.method == http.MethodGet && segs[2] == "image":
+		album, ok := s.albums[segs[1]]
+		if !ok || !containsString(album.imageIDs, segs[3]) {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the id, "+segs[3])
+		}
+		return s.getImageView(s.images[segs[3]], req.account, false), nil
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find the requested endpoint")
+}
+
+func (s *Server) routeGallery(req *request, segs []string) (any, *apiError) {
+	switch {
+	case len(segs) == 3 && req.method == http.MethodGet && (segs[1] == "image" || segs[1] == "album"):
+		entry, ok := s.gallery[segs[2]]
+		if !ok || entry.isAlbum != (segs[1] == "album") {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find a gallery "+segs[1]+" with the id, "+segs[2])
+		}
+		return s.getGalleryView(entry, req.account), nil
+	case len(segs) >= 2 && segs[1] == "search" && req.method == http.MethodGet:
+		return s.searchGallery(req, segs[2:])
+	case len(segs) == 5 && req.method == http.MethodGet:
+		return s.listGallery(req, segs[2], segs[4], nil)
+	case len(segs) == 2:
+		switch req.method {
+		case http.MethodGet:
+			entry, ok := s.gallery[segs[1]]
+			if !ok {
+				return nil, newAPIError(http.StatusNotFound, "Unable to find a gallery item with the id, "+segs[1])
+			}
+			return s.getGalleryView(entry, req.account), nil
+		case http.MethodPost:
+			return s.submitToGallery(req, segs[1])
+		case http.MethodDelete:
+			return s.removeFromGallery(req, segs[1])
+		}
+	case len(segs) == 3 && segs[2] == "votes" && req.method == http.MethodGet:
+		entry, ok := s.gallery[segs[1]]
+		if !ok {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find a gallery item with the id, "+segs[1])
+		}
+		ups, downs := entry.countVotes()
+		return &wotoImgur.GalleryVotes{Ups: ups, Downs: downs}, nil
+	case len(segs) == 4 && segs[2] == "vote" && req.method == http.MethodPost:
+		return s.voteGallery(req, segs[1], wotoImgur.Vote(segs[3]))
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find the requested endpoint")
+}
+
+func (s *Server) getAccount(req *request, username string) (any, *apiError) {
+	if username == wotoImgur.AccountMe {
+		if req.account == "" {
+			return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+		}
+		username = req.account
+	}
+
+	return &wotoImgur.Account{URL: username}, nil
+}
+
+// upload stores an uploaded image or video, adding it to the album
+// given by its id or deletehash, if any.
+func (s *Server) upload(req *request) (any, *apiError) {
+	data, contentType, apiErr := getUploadData(req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var album *storedAlbum
+	if albumID := req.form.Get("album"); albumID != "" {
+		album, apiErr = s.findAlbum(req, albumID)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+	}
+
+	name := req.form.Get("name")
+	if name == "" {
+		name = req.fileName
+	}
+	if contentType == "" {
+		contentType = getContentType(name)
+	}
+
+	info := wotoImgur.ImageInfo{
+		Title:       req.form.Get("title"),
+		Description: req.form.Get("description"),
+		Name:        name,
+		MimeType:    contentType,
+	}
+	if strings.HasPrefix(contentType, "video/") {
+		info.HasSound = req.form.Get("disable_audio") != "1"
+		info.Processing = &wotoImgur.ProcessingInfo{Status: wotoImgur.ProcessingStatusCompleted}
+	}
+
+	uploaded := s.addImage(info, data, req.account)
+	if album != nil {
+		album.imageIDs = append(album.imageIDs, uploaded.ID)
+	}
+
+	return uploaded, nil
+}
+
+func (s *Server) updateImage(req *request, idOrDeleteHash string) (any, *apiError) {
+	img, apiErr := s.findImage(req, idOrDeleteHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if title := req.form.Get("title"); title != "" {
+		img.info.Title = title
+	}
+	if description := req.form.Get("description"); description != "" {
+		img.info.Description = description
+	}
+
+	return true, nil
+}
+
+func (s *Server) deleteImage(req *request, idOrDeleteHash string) (any, *apiError) {
+	img, apiErr := s.findImage(req, idOrDeleteHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	delete(s.images, img.info.ID)
+	delete(s.gallery, img.info.ID)
+	for _, album := range s.albums {
+		album.imageIDs = removeStrings(album.imageIDs, img.info.ID)
+	}
+
+	return true, nil
+}
+
+func (s *Server) createAlbum(req *request) (any, *apiError) {
+	ids, apiErr := s.getFormImageIDs(req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	album := s.addAlbum(wotoImgur.AlbumInfo{
+		Title:       req.form.Get("title"),
+		Description: req.form.Get("description"),
+		Privacy:     req.form.Get("privacy"),
+		Layout:      req.form.Get("layout"),
+		Cover:       req.form.Get("cover"),
+	}, req.account)
+	album.imageIDs = ids
+
+	// like imgur, only the id and deletehash are returned.
+	return &wotoImgur.AlbumInfo{
+		ID:         album.info.ID,
+		DeleteHash: album.info.DeleteHash,
+	}, nil
+}
+
+func (s *Server) updateAlbum(req *request, idOrDeleteHash string) (any, *apiError) {
+	album, apiErr := s.findAlbum(req, idOrDeleteHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	fields := map[string]*string{
+		"title":       &album.info.Title,
+		"description": &album.info.Description,
+		"privacy":     &album.info.Privacy,
+		"layout":      &album.info.Layout,
+		"cover":       &album.info.Cover,
+	}
+	for key, field := range fields {
+		if value := req.form.Get(key); value != "" {
+			*field = value
+		}
+	}
+
+	if _, ok := req.form["ids[]"]; ok {
+		ids, apiErr := s.getFormImageIDs(req)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		album.imageIDs = ids
+	}
+
+	return true, nil
+}
+
+// changeAlbumImages adds, removes or sets (replaces) the images of an album.
+func (s *Server) changeAlbumImages(req *request, idOrDeleteHash, change string) (any, *apiError) {
+	album, apiErr := s.findAlbum(req, idOrDeleteHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	ids, apiErr := s.getFormImageIDs(req)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	switch change {
+	case "add":
+		for _, id := range ids {
+			if !containsString(album.imageIDs, id) {
+				album.imageIDs = append(album.imageIDs, id)
+			}
+		}
+	case "remove_images":
+		album.imageIDs = removeStrings(album.imageIDs, ids...)
+	default:
+		album.imageIDs = ids
+	}
+
+	return true, nil
+}
+
+func (s *Server) deleteAlbum(req *request, idOrDeleteHash string) (any, *apiError) {
+	album, apiErr := s.findAlbum(req, idOrDeleteHash)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	delete(s.albums, album.info.ID)
+	delete(s.gallery, album.info.ID)
+
+	return true, nil
+}
+
+func (s *Server) submitToGallery(req *request, id string) (any, *apiError) {
+	if req.account == "" {
+		return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+	}
+
+	owner, isAlbum, ok := s.getOwner(id)
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "Unable to find an image or album with the id, "+id)
+	}
+	if owner != req.account {
+		return nil, newAPIError(http.StatusForbidden, "Permission denied")
+	}
+
+	title := req.form.Get("title")
+	if title == "" {
+		return nil, newAPIError(http.StatusBadRequest, "A title is required")
+	}
+
+	entry, ok := s.gallery[id]
+	if !ok {
+		entry = &galleryEntry{
+			id:       id,
+			isAlbum:  isAlbum,
+			datetime: nowUnix(),
+			votes:    make(map[string]wotoImgur.Vote),
+		}
+		s.gallery[id] = entry
+	}
+
+	entry.title = title
+	entry.topic = req.form.Get("topic")
+	entry.mature = req.form.Get("mature") == "1"
+	entry.tags = nil
+	if tags := req.form.Get("tags"); tags != "" {
+		entry.tags = strings.Split(tags, ",")
+	}
+
+	return true, nil
+}
+
+func (s *Server) removeFromGallery(req *request, id string) (any, *apiError) {
+	if req.account == "" {
+		return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+	}
+	if _, ok := s.gallery[id]; !ok {
+		return nil, newAPIError(http.StatusNotFound, "Unable to find a gallery item with the id, "+id)
+	}
+	if owner, _, _ := s.getOwner(id); owner != req.account {
+		return nil, newAPIError(http.StatusForbidden, "Permission denied")
+	}
+
+	delete(s.gallery, id)
+	return true, nil
+}
+
+func (s *Server) voteGallery(req *request, id string, vote wotoImgur.Vote) (any, *apiError) {
+	if req.account == "" {
+		return nil, newAPIError(http.StatusUnauthorized, "Authentication required")
+	}
+
+	entry, ok := s.gallery[id]
+	if !ok {
+		return nil, newAPIError(http.StatusNotFound, "Unable to find a gallery item with the id, "+id)
+	}
+
+	switch vote {
+	case wotoImgur.VoteUp, wotoImgur.VoteDown:
+		entry.votes[req.account] = vote
+	case wotoImgur.VoteVeto:
+		delete(entry.votes, req.account)
+	default:
+		return nil, newAPIError(http.StatusBadRequest, "Invalid vote "+string(vote))
+	}
+
+	return true, nil
+}
+
+// listGallery returns a page of the gallery items passing the filter,
+// in the given sort order.
+func (s *Server) listGallery(req *request, sortBy, pageStr string, filter func(entry *galleryEntry) bool) (any, *apiError) {
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 0 {
+		return nil, newAPIError(http.StatusBadRequest, "Invalid page "+pageStr)
+	}
+
+	entries := make([]*galleryEntry, 0, len(s.gallery))
+	for _, entry := range s.gallery {
+		if filter == nil || filter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if sortBy != string(wotoImgur.GallerySortTime) {
+			aUps, aDowns := a.countVotes()
+			bUps, bDowns := b.countVotes()
+			if aUps-aDowns != bUps-bDowns {
+				return aUps-aDowns > bUps-bDowns
+			}
+		}
+		if a.datetime != b.datetime {
+			return a.datetime > b.datetime
+		}
+		return a.id < b.id
+	})
+
+	items := []wotoImgur.GalleryItem{}
+	for i := page * GalleryPageSize; i < len(entries) && i < (page+1)*GalleryPageSize; i++ {
+		items = append(items, *s.getGalleryView(entries[i], req.account))
+	}
+
+	return items, nil
+}
+
+// searchGallery matches the search query against the titles and tags
+// of the gallery items. segs are the optional sort, window and page.
+func (s *Server) searchGallery(req *request, segs []string) (any, *apiError) {
+	sortBy, page := string(wotoImgur.GallerySortTime), "0"
+	if len(segs) >= 1 {
+		sortBy = segs[0]
+	}
+	if len(segs) >= 3 {
+		page = segs[2]
+	}
+
+	all := strings.Fields(strings.ToLower(req.form.Get("q") + " " + req.form.Get("q_all")))
+	anyOf := strings.Fields(strings.ToLower(req.form.Get("q_any")))
+	not := strings.Fields(strings.ToLower(req.form.Get("q_not")))
+	exactly := strings.ToLower(req.form.Get("q_exactly"))
+	if len(all) == 0 && len(anyOf) == 0 && exactly == "" {
+		return nil, newAPIError(http.StatusBadRequest, "No search query was sent")
+	}
+
+	return s.listGallery(req, sortBy, page, func(entry *galleryEntry) bool {
+		text := strings.ToLower(entry.title + " " + strings.Join(entry.tags, " "))
+		for _, word := range all {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+		for _, word := range not {
+			if strings.Contains(text, word) {
+				return false
+			}
+		}
+		if exactly != "" && !strings.Contains(text, exactly) {
+			return false
+		}
+		if len(anyOf) == 0 {
+			return true
+		}
+		for _, word := range anyOf {
+			if strings.Contains(text, word) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *Server) addImage(info wotoImgur.ImageInfo, data []byte, owner string) *wotoImgur.ImageInfo {
+	if info.ID == "" {
+		info.ID = s.newUniqueID()
+	}
+	if info.DeleteHash == "" {
+		info.DeleteHash = newID(deleteHashLength)
+	}
+	if info.Datetime == 0 {
+		info.Datetime = nowUnix()
+	}
+
+	if width, height, contentType := decodeImageSize(data); contentType != "" {
+		info.Width, info.Height, info.MimeType = width, height, contentType
+	}
+	if info.MimeType == "" {
+		info.MimeType = "image/png"
+	}
+	if info.Size == 0 {
+		info.Size = len(data)
+	}
+	if info.Link == "" {
+		info.Link = linkPrefix + info.ID + getExtension(info.MimeType)
+	}
+	if strings.HasPrefix(info.MimeType, "video/") && info.Mp4 == "" {
+		// videos are processed right away by the fake server.
+		info.Mp4 = linkPrefix + info.ID + ".mp4"
+		info.Gifv = linkPrefix + info.ID + ".gifv"
+	}
+
+	img := &storedImage{
+		info:      info,
+		data:      append([]byte(nil), data...),
+		owner:     owner,
+		favorites: make(map[string]bool),
+	}
+	s.images[info.ID] = img
+
+	return s.getImageView(img, owner, true)
+}
+
+func (s *Server) addAlbum(info wotoImgur.AlbumInfo, owner string) *storedAlbum {
+	if info.ID == "" {
+		info.ID = s.newUniqueID()
+	}
+	if info.DeleteHash == "" {
+		info.DeleteHash = newID(deleteHashLength)
+	}
+	if info.DateTime == 0 {
+		info.DateTime = nowUnix()
+	}
+	if info.Privacy == "" {
+		info.Privacy = string(wotoImgur.AlbumPrivacyHidden)
+	}
+	info.Link = albumLinkPrefix + info.ID
+	info.AccountURL = owner
+
+	album := &storedAlbum{
+		info:      info,
+		owner:     owner,
+		favorites: make(map[string]bool),
+	}
+	s.albums[info.ID] = album
+
+	return album
+}
+
+// findImage returns the image with the id or deletehash, if the request
+// is allowed to modify it: anonymous images can only be modified by
+// their deletehash, the ones of an account by the account too.
+func (s *Server) findImage(req *request, idOrDeleteHash string) (*storedImage, *apiError) {
+	if img, ok := s.images[idOrDeleteHash]; ok {
+		if img.owner == "" || img.owner != req.account {
+			return nil, newAPIError(http.StatusForbidden, "Permission denied")
+		}
+		return img, nil
+	}
+
+	for _, img := range s.images {
+		if img.info.DeleteHash == idOrDeleteHash {
+			return img, nil
+		}
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the id, "+idOrDeleteHash)
+}
+
+// findAlbum is like findImage, but for albums.
+func (s *Server) findAlbum(req *request, idOrDeleteHash string) (*storedAlbum, *apiError) {
+	if album, ok := s.albums[idOrDeleteHash]; ok {
+		if album.owner == "" || album.owner != req.account {
+			return nil, newAPIError(http.StatusForbidden, "Permission denied")
+		}
+		return album, nil
+	}
+
+	for _, album := range s.albums {
+		if album.info.DeleteHash == idOrDeleteHash {
+			return album, nil
+		}
+	}
+
+	return nil, newAPIError(http.StatusNotFound, "Unable to find an album with the id, "+idOrDeleteHash)
+}
+
+// getFormImageIDs returns the ids of the images passed as ids[] or
+// deletehashes[] in the form of the request.
+func (s *Server) getFormImageIDs(req *request) ([]string, *apiError) {
+	ids := []string{}
+	for _, id := range req.form["ids[]"] {
+		if _, ok := s.images[id]; !ok {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the id, "+id)
+		}
+		ids = append(ids, id)
+	}
+
+	for _, deleteHash := range req.form["deletehashes[]"] {
+		found := false
+		for _, img := range s.images {
+			if img.info.DeleteHash == deleteHash {
+				ids = append(ids, img.info.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, newAPIError(http.StatusNotFound, "Unable to find an image with the deletehash, "+deleteHash)
+		}
+	}
+
+	return ids, nil
+}
+
+// getOwner returns the owner of the image or album with the id.
+func (s *Server) getOwner(id string) (string, bool, bool) {
+	if img, ok := s.images[id]; ok {
+		return img.owner, false, true
+	}
+	if album, ok := s.albums[id]; ok {
+		return album.owner, true, true
+	}
+	return "", false, false
+}
+
+// getImageView returns the image as seen by the account; the deletehash
+// is only shown to its owner, unless withDeleteHash is true.
+func (s *Server) getImageView(img *storedImage, account string, withDeleteHash bool) *wotoImgur.ImageInfo {
+	info := img.info
+	if !withDeleteHash && (img.owner == "" || img.owner != account) {
+		info.DeleteHash = ""
+		info.Name = ""
+	}
+
+	info.Favorite = img.favorites[account]
+	if entry, ok := s.gallery[info.ID]; ok {
+		info.InGallery = true
+		info.Vote = string(entry.votes[account])
+	}
+
+	return &info
+}
+
+// getAlbumView is like getImageView, but for albums.
+func (s *Server) getAlbumView(album *storedAlbum, account string, withDeleteHash bool) *wotoImgur.AlbumInfo {
+	info := album.info
+	if !withDeleteHash && (album.owner == "" || album.owner != account) {
+		info.DeleteHash = ""
+	}
+
+	info.Images = []wotoImgur.ImageInfo{}
+	for _, id := range album.imageIDs {
+		if img, ok := s.images[id]; ok {
+			info.Images = append(info.Images, *s.getImageView(img, account, false))
+		}
+	}
+	info.ImagesCount = len(info.Images)
+	if info.Cover == "" && len(info.Images) != 0 {
+		info.Cover = info.Images[0].ID
+	}
+
+	info.Favorite = album.favorites[account]
+	_, info.InGallery = s.gallery[info.ID]
+
+	return &info
+}
+
+// getGalleryView returns the gallery item of the entry as seen by the account.
+func (s *Server) getGalleryView(entry *galleryEntry, account string) *wotoImgur.GalleryItem {
+	ups, downs := entry.countVotes()
+	vote := string(entry.votes[account])
+
+	if entry.isAlbum {
+		album := s.getAlbumView(s.albums[entry.id], account, false)
+		return &wotoImgur.GalleryItem{Album: &wotoImgur.GalleryAlbumInfo{
+			ID:          album.ID,
+			Title:       entry.title,
+			Description: album.Description,
+			DateTime:    entry.datetime,
+			Cover:       album.Cover,
+			AccountURL:  album.AccountURL,
+			Privacy:     album.Privacy,
+			Layout:      album.Layout,
+			Link:        album.Link,
+			Ups:         ups,
+			Downs:       downs,
+			Points:      ups - downs,
+			Score:       ups - downs,
+			IsAlbum:     true,
+			Vote:        vote,
+			Favorite:    album.Favorite,
+			Nsfw:        entry.mature,
+			Topic:       entry.topic,
+			ImagesCount: album.ImagesCount,
+			Images:      album.Images,
+		}}
+	}
+
+	img := s.getImageView(s.images[entry.id], account, false)
+	return &wotoImgur.GalleryItem{Image: &wotoImgur.GalleryImageInfo{
+		ID:          img.ID,
+		Title:       entry.title,
+		Description: img.Description,
+		Datetime:    entry.datetime,
+		MimeType:    img.MimeType,
+		Animated:    img.Animated,
+		Width:       img.Width,
+		Height:      img.Height,
+		Size:        img.Size,
+		Link:        img.Link,
+		Vote:        vote,
+		Favorite:    img.Favorite,
+		Nsfw:        entry.mature,
+		Topic:       entry.topic,
+		AccountURL:  s.images[entry.id].owner,
+		Ups:         ups,
+		Downs:       downs,
+		Points:      ups - downs,
+		Score:       ups - downs,
+	}}
+}
+
+func (s *Server) newUniqueID() string {
+	for {
+		id := newID(idLength)
+		if _, ok := s.images[id]; ok {
+			continue
+		}
+		if _, ok := s.albums[id]; ok {
+			continue
+		}
+		return id
+	}
+}
+
+// --------------------------------------------------------
+
+// Client returns a http client sending its requests through the recorder,
+// to be used as the HTTPClient of a wotoImgur.ClientConfig.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode returns whether the recorder records or replays; never ModeAuto.
+func (r *Recorder) Mode() RecorderMode {
+	return r.mode
+}
+
+// RoundTrip records or replays the request, depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, body, err := recordRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("wotoImgurtest: could not read request %v %v: %w", req.Method, req.URL, err)
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded, body)
+}
+
+// replay answers the request with the first unused interaction matching it.
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+
+		r.used[i] = true
+		return interaction.Response.toResponse(req), nil
+	}
+
+	return nil, fmt.Errorf("wotoImgurtest: no recorded interaction left for %v %v in %v",
+		recorded.Method, recorded.Path, r.path)
+}
+
+// record sends the request through the transport and saves it to the
+// cassette along with its response.
+func (r *Recorder) record(req *http.Request, recorded *RecordedRequest, body []byte) (*http.Response, error) {
+	outReq := req.Clone(req.Context())
+	outReq.Body = io.NopCloser(bytes.NewReader(body))
+	outReq.ContentLength = int64(len(body))
+	if req.Body == nil {
+		outReq.Body = nil
+	}
+
+	res, err := r.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: recorded,
+		Response: &RecordedResponse{
+			Status:  res.StatusCode,
+			Headers: res.Header.Clone(),
+			Body:    string(redactBody(resBody)),
+		},
+	})
+
+	if err = r.save(); err != nil {
+		return nil, fmt.Errorf("wotoImgurtest: could not save cassette %v: %w", r.path, err)
+	}
+
+	return res, nil
+}
+
+// save writes the cassette to its file.
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so a crash never leaves
+	// a half-written cassette behind.
+	tmpPath := r.path + ".tmp"
+	if err = os.WriteFile(tmpPath, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, r.path)
+}
+
+// --------------------------------------------------------
+
+// matches returns true if the other request has the same method, path,
+// query and form fields, ignoring the redacted ones.
+func (r *RecordedRequest) matches(other *RecordedRequest) bool {
+	return r.Method == other.Method && r.Path == other.Path &&
+		r.Form.Encode() == other.Form.Encode()
+}
+
+// --------------------------------------------------------
+
+// toResponse returns the recorded response as the response to req.
+func (r *RecordedResponse) toResponse(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(r.Status) + " " + http.StatusText(r.Status),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// --------------------------------------------------------
+
+// isUpload returns true if the request uploads a new image or video.
+func (r *request) isUpload() bool {
+	return r.method == http.MethodPost && len(r.segments) == 1 &&
+		(r.segments[0] == "image" || r.segments[0] == "upload")
+}
+
+// --------------------------------------------------------
+
+func (e *galleryEntry) countVotes() (int, int) {
+	ups, downs := 0, 0
+	for _, vote := range e.votes {
+		if vote == wotoImgur.VoteUp {
+			ups++
+		} else if vote == wotoImgur.VoteDown {
+			downs++
+		}
+	}
+	return ups, downs
+}
@@ -0,0 +1,26 @@
+package wotoImgurtest
+
+// redactedHeaders are the request headers whose values are
+// never written to a cassette.
+var redactedHeaders = []string{
+	"Authorization",
+	"X-RapidAPI-Key",
+	"Cookie",
+}
+
+// redactedFields are the form fields whose values are never written to
+// a cassette; they're ignored when matching requests.
+var redactedFields = []string{
+	"client_id",
+	"client_secret",
+	"refresh_token",
+	"code",
+	"pin",
+}
+
+// redactedBodyFields are the fields of JSON response bodies whose values
+// are never written to a cassette, such as the tokens returned by oauth2.
+var redactedBodyFields = []string{
+	"access_token",
+	"refresh_token",
+}